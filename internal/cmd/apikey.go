@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/repository"
+	"github.com/miladbarzideh/shortify/internal/domain/service"
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+var cmdAPIKey = func(log *logrus.Logger, postgresDb *gorm.DB) *cobra.Command {
+	apiKeyService := service.NewAPIKeyService(log, repository.NewAPIKeyRepository(log, postgresDb, infra.NOOPTelemetry))
+	cmd := &cobra.Command{
+		Use:   "apikey",
+		Short: "Manage the API keys of the write endpoints",
+	}
+
+	cmdCreate := &cobra.Command{
+		Use:   "create",
+		Short: "Create an API key; the key is printed once and cannot be recovered",
+		Run: func(cmd *cobra.Command, args []string) {
+			owner, _ := cmd.Flags().GetString("owner")
+			admin, _ := cmd.Flags().GetBool("admin")
+			rawKey, key, err := apiKeyService.Create(cmd.Context(), owner, admin)
+			if err != nil {
+				log.Fatalf("failed to create api key: %v", err)
+			}
+
+			fmt.Printf("id:    %d\nowner: %s\nadmin: %t\nkey:   %s\n", key.ID, key.Owner, key.Admin, rawKey)
+		},
+	}
+	cmdCreate.Flags().StringP("owner", "o", "", "Owner of the key, recorded on the links and changes made with it")
+	cmdCreate.Flags().Bool("admin", false, "Allow the key to manage the links of every owner")
+	_ = cmdCreate.MarkFlagRequired("owner")
+
+	cmdList := &cobra.Command{
+		Use:   "list",
+		Short: "List API keys",
+		Run: func(cmd *cobra.Command, args []string) {
+			keys, err := apiKeyService.List(cmd.Context())
+			if err != nil {
+				log.Fatalf("failed to list api keys: %v", err)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tOWNER\tADMIN\tPREFIX\tCREATED\tREVOKED")
+			for _, key := range keys {
+				revoked := "-"
+				if key.RevokedAt != nil {
+					revoked = key.RevokedAt.Format(time.RFC3339)
+				}
+
+				fmt.Fprintf(w, "%d\t%s\t%t\t%s\t%s\t%s\n", key.ID, key.Owner, key.Admin, key.Prefix, key.CreatedAt.Format(time.RFC3339), revoked)
+			}
+
+			_ = w.Flush()
+		},
+	}
+
+	cmdRevoke := &cobra.Command{
+		Use:   "revoke <id>",
+		Short: "Revoke an API key",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			id, err := strconv.ParseUint(args[0], 10, 0)
+			if err != nil {
+				log.Fatalf("invalid api key id '%s'", args[0])
+			}
+
+			if err = apiKeyService.Revoke(cmd.Context(), uint(id)); err != nil {
+				log.Fatalf("failed to revoke api key: %v", err)
+			}
+
+			fmt.Printf("revoked api key %d\n", id)
+		},
+	}
+
+	cmd.AddCommand(cmdCreate, cmdList, cmdRevoke)
+
+	return cmd
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/repository"
+	"github.com/miladbarzideh/shortify/internal/domain/service"
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+var cmdBlocklist = func(log *logrus.Logger, postgresDb *gorm.DB) *cobra.Command {
+	blocklistService := service.NewBlocklistService(log, repository.NewBlockedHostRepository(log, postgresDb, infra.NOOPTelemetry))
+	cmd := &cobra.Command{
+		Use:   "blocklist",
+		Short: "Manage the destination hosts links must not point to",
+	}
+
+	cmdAdd := &cobra.Command{
+		Use:   "add <host>",
+		Short: "Block a host and its subdomains",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			reason, _ := cmd.Flags().GetString("reason")
+			blocked, err := blocklistService.Block(cmd.Context(), args[0], reason)
+			if err != nil {
+				log.Fatalf("failed to block host: %v", err)
+			}
+
+			fmt.Printf("blocked host %s\n", blocked.Host)
+		},
+	}
+	cmdAdd.Flags().StringP("reason", "r", "", "Why the host is blocked, e.g. phishing")
+
+	cmdList := &cobra.Command{
+		Use:   "list",
+		Short: "List blocked hosts",
+		Run: func(cmd *cobra.Command, args []string) {
+			hosts, err := blocklistService.List(cmd.Context())
+			if err != nil {
+				log.Fatalf("failed to list blocked hosts: %v", err)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "HOST\tREASON\tCREATED")
+			for _, host := range hosts {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", host.Host, host.Reason, host.CreatedAt.Format(time.RFC3339))
+			}
+
+			_ = w.Flush()
+		},
+	}
+
+	cmdRemove := &cobra.Command{
+		Use:   "remove <host>",
+		Short: "Unblock a host",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := blocklistService.Unblock(cmd.Context(), args[0]); err != nil {
+				log.Fatalf("failed to unblock host: %v", err)
+			}
+
+			fmt.Printf("unblocked host %s\n", args[0])
+		},
+	}
+
+	cmd.AddCommand(cmdAdd, cmdList, cmdRemove)
+
+	return cmd
+}
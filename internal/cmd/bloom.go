@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/repository"
+	"github.com/miladbarzideh/shortify/internal/domain/service"
+	"github.com/miladbarzideh/shortify/internal/infra"
+	"github.com/miladbarzideh/shortify/pkg/bloom"
+)
+
+// bloomPrefix keys the Bloom filter of existing short codes in Redis.
+const bloomPrefix = "bloom:short-codes"
+
+func newBloomFilter(cfg *infra.Config, redis *redis.Client) (*bloom.Filter, error) {
+	return bloom.New(redis, bloomPrefix, cfg.Shortener.Bloom.ExpectedItems, cfg.Shortener.Bloom.FalsePositiveRate)
+}
+
+var cmdBloom = func(cfg *infra.Config, log *logrus.Logger, postgresDb *gorm.DB, redis *redis.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bloom",
+		Short: "Manage the Bloom filter of existing short codes",
+	}
+
+	cmdRebuild := &cobra.Command{
+		Use:   "rebuild",
+		Short: "Build the filter from the stored links, e.g. after enabling it or changing its size",
+		Run: func(cmd *cobra.Command, args []string) {
+			filter, err := newBloomFilter(cfg, redis)
+			if err != nil {
+				log.Fatalf("failed to create bloom filter: %v", err)
+			}
+
+			urlRepository := repository.NewRepository(log, postgresDb, infra.NOOPTelemetry)
+			count, err := service.NewFilterService(log, urlRepository, filter).Rebuild(cmd.Context())
+			if err != nil {
+				log.Fatalf("failed to rebuild bloom filter: %v", err)
+			}
+
+			fmt.Printf("added %d short codes to the bloom filter\n", count)
+		},
+	}
+
+	cmd.AddCommand(cmdRebuild)
+
+	return cmd
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/repository"
+	"github.com/miladbarzideh/shortify/internal/domain/service"
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+var cmdDomain = func(log *logrus.Logger, postgresDb *gorm.DB) *cobra.Command {
+	domainService := service.NewDomainService(log, repository.NewDomainRepository(log, postgresDb, infra.NOOPTelemetry))
+	cmd := &cobra.Command{
+		Use:   "domain",
+		Short: "Manage the branded short domains links can be created on",
+	}
+
+	cmdAdd := &cobra.Command{
+		Use:   "add <host>",
+		Short: "Register a short domain; its DNS must point to this server",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			domain, err := domainService.Add(cmd.Context(), args[0])
+			if err != nil {
+				log.Fatalf("failed to add domain: %v", err)
+			}
+
+			fmt.Printf("added domain %s\n", domain.Host)
+		},
+	}
+
+	cmdList := &cobra.Command{
+		Use:   "list",
+		Short: "List short domains",
+		Run: func(cmd *cobra.Command, args []string) {
+			domains, err := domainService.List(cmd.Context())
+			if err != nil {
+				log.Fatalf("failed to list domains: %v", err)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "HOST\tCREATED")
+			for _, domain := range domains {
+				fmt.Fprintf(w, "%s\t%s\n", domain.Host, domain.CreatedAt.Format(time.RFC3339))
+			}
+
+			_ = w.Flush()
+		},
+	}
+
+	cmdRemove := &cobra.Command{
+		Use:   "remove <host>",
+		Short: "Remove a short domain; its links are kept but no longer redirect",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := domainService.Remove(cmd.Context(), args[0]); err != nil {
+				log.Fatalf("failed to remove domain: %v", err)
+			}
+
+			fmt.Printf("removed domain %s\n", args[0])
+		},
+	}
+
+	cmd.AddCommand(cmdAdd, cmdList, cmdRemove)
+
+	return cmd
+}
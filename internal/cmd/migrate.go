@@ -6,16 +6,42 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/domain/repository"
 )
 
+// backfillLongURLs computes the hash model.HashLongURL would give, the hex encoded SHA-256 of the UTF-8 long URL.
+const backfillLongURLs = `UPDATE urls
+SET long_url_hash = encode(sha256(convert_to(long_url, 'UTF8')), 'hex'),
+    original_url  = COALESCE(NULLIF(original_url, ''), long_url)
+WHERE long_url_hash IS NULL OR long_url_hash = ''`
+
 var cmdMigrate = func(log *logrus.Logger, postgresDb *gorm.DB) *cobra.Command {
 	return &cobra.Command{
 		Use:   "migrate",
 		Short: "Migrate the database",
 		Run: func(cmd *cobra.Command, args []string) {
-			if err := postgresDb.AutoMigrate(&model.URL{}); err != nil {
+			if err := postgresDb.AutoMigrate(&model.URL{}, &model.Visit{}, &model.AuditLog{}, &model.APIKey{}, &model.Domain{}, &model.BlockedHost{}); err != nil {
 				log.Fatalf("failed to migrate database: %v", err)
 			}
+
+			// short codes used to be unique across all links, they are now unique per domain
+			if err := postgresDb.Exec("ALTER TABLE urls DROP CONSTRAINT IF EXISTS uni_urls_short_code").Error; err != nil {
+				log.Fatalf("failed to drop the global short code constraint: %v", err)
+			}
+
+			// stats filter visits by domain, the index without it is replaced by one that starts with it
+			if err := postgresDb.Exec("DROP INDEX IF EXISTS idx_visits_short_code_visited_at").Error; err != nil {
+				log.Fatalf("failed to drop the visits index without domain: %v", err)
+			}
+
+			// links created before deduplication have neither a destination hash nor a submitted URL
+			if err := postgresDb.Exec(backfillLongURLs).Error; err != nil {
+				log.Fatalf("failed to backfill long URL hashes: %v", err)
+			}
+
+			if err := postgresDb.Exec("CREATE SEQUENCE IF NOT EXISTS " + repository.ShortCodeSequence).Error; err != nil {
+				log.Fatalf("failed to create short code sequence: %v", err)
+			}
 		},
 	}
 }
@@ -34,6 +34,10 @@ func Execute() {
 		"Optional port number.Default value will be read from the config file")
 	rooCmd.AddCommand(cmdServe)
 	rooCmd.AddCommand(cmdMigrate(log, postgresDb))
+	rooCmd.AddCommand(cmdAPIKey(log, postgresDb))
+	rooCmd.AddCommand(cmdDomain(log, postgresDb))
+	rooCmd.AddCommand(cmdBlocklist(log, postgresDb))
+	rooCmd.AddCommand(cmdBloom(cfg, log, postgresDb, redis))
 	if err = rooCmd.Execute(); err != nil {
 		log.Fatalf("failed to execute root command %s", err)
 	}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -20,6 +22,9 @@ import (
 	"github.com/miladbarzideh/shortify/internal/domain/service"
 	"github.com/miladbarzideh/shortify/internal/infra"
 	"github.com/miladbarzideh/shortify/pkg/generator"
+	"github.com/miladbarzideh/shortify/pkg/ratelimit"
+	"github.com/miladbarzideh/shortify/pkg/urlpolicy"
+	"github.com/miladbarzideh/shortify/pkg/workerpool"
 )
 
 type Server struct {
@@ -28,6 +33,7 @@ type Server struct {
 	db        *gorm.DB
 	redis     *redis.Client
 	telemetry *infra.TelemetryProvider
+	pool      *workerpool.Pool
 }
 
 func NewServer(
@@ -47,11 +53,20 @@ func NewServer(
 }
 
 func (s *Server) Run() {
+	meter := s.telemetry.MeterProvider.Meter("workerPool")
+	s.pool = workerpool.New(s.logger, s.cfg.WorkerPool.WorkerCount, s.cfg.WorkerPool.QueueSize, meter)
 	app := echo.New()
-	s.mapHandlers(app)
+	extractor, err := s.newIPExtractor()
+	if err != nil {
+		s.logger.Fatalf("failed to parse trusted proxies: %v", err)
+	}
+
+	// the client IP keys rate limits and password lockouts, so forwarding headers are only trusted from known proxies
+	app.IPExtractor = extractor
 	// https://echo.labstack.com/docs/cookbook/graceful-shutdown
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer stop()
+	s.mapHandlers(ctx, app)
 	go func() {
 		address := fmt.Sprintf(":%s", s.cfg.Server.Port)
 		if err := app.Start(address); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -65,17 +80,176 @@ func (s *Server) Run() {
 	if err := app.Shutdown(ctx); err != nil {
 		s.logger.Fatal(err)
 	}
+
+	// Drain background jobs only after the server stopped accepting requests that could enqueue new ones
+	if err := s.pool.Shutdown(ctx); err != nil {
+		s.logger.Errorf("failed to drain worker pool: %v", err)
+	}
 }
 
-func (s *Server) mapHandlers(app *echo.Echo) {
+// mapHandlers wires the app; background listeners run until ctx is done.
+func (s *Server) mapHandlers(ctx context.Context, app *echo.Echo) {
 	urlRepository := repository.NewRepository(s.logger, s.db, s.telemetry)
-	urlCacheRepository := repository.NewCacheRepository(s.logger, s.redis, s.telemetry)
-	gen := generator.NewGenerator(s.cfg.Shortener.CodeLength)
-	urlService := service.NewService(s.logger, s.cfg, urlRepository, urlCacheRepository, gen, s.telemetry)
-	urlHandler := controller.NewHandler(s.logger, s.cfg, urlService, s.telemetry)
-	groupV1 := app.Group("/api/v1")
-	groupV1.POST("/urls/shorten", urlHandler.CreateShortURL())
-	groupV1.GET("/urls/:url", urlHandler.RedirectToLongURL())
+	sharedCacheRepository := repository.NewCacheRepository(s.logger, s.redis, s.telemetry)
+	var urlCacheRepository service.URLCacheRepository = sharedCacheRepository
+	if s.cfg.Cache.Local.Enabled {
+		localCacheRepository := repository.NewLocalCacheRepository(s.logger, sharedCacheRepository, s.redis, s.cfg.Cache.Local, s.telemetry)
+		go localCacheRepository.Listen(ctx)
+		urlCacheRepository = localCacheRepository
+	}
+
+	visitRepository := repository.NewVisitRepository(s.logger, s.db, s.telemetry)
+	auditRepository := repository.NewAuditRepository(s.logger, s.db, s.telemetry)
+	apiKeyRepository := repository.NewAPIKeyRepository(s.logger, s.db, s.telemetry)
+	passwordAttemptRepository := repository.NewPasswordAttemptRepository(s.redis, s.telemetry)
+	domainRepository := repository.NewDomainRepository(s.logger, s.db, s.telemetry)
+	blockedHostRepository := repository.NewBlockedHostRepository(s.logger, s.db, s.telemetry)
+	gen, err := s.newGenerator()
+	if err != nil {
+		s.logger.Fatalf("failed to create short code generator: %v", err)
+	}
+
+	domainService := service.NewDomainService(s.logger, domainRepository)
+	rules, err := s.newDestinationRules()
+	if err != nil {
+		s.logger.Fatalf("failed to load destination policy: %v", err)
+	}
+
+	blocklistService := service.NewBlocklistService(s.logger, blockedHostRepository)
+	policyService := service.NewPolicyService(s.logger, s.cfg, rules, blocklistService, domainService, s.telemetry)
+	var codeFilter service.CodeFilter
+	if s.cfg.Shortener.Bloom.Enabled {
+		if codeFilter, err = newBloomFilter(s.cfg, s.redis); err != nil {
+			s.logger.Fatalf("failed to create bloom filter: %v", err)
+		}
+	}
+
+	var lookupLock service.LookupLock
+	if s.cfg.Cache.Lock.Enabled {
+		lookupLock = repository.NewLookupLockRepository(s.redis, s.telemetry)
+	}
+
+	urlService := service.NewService(s.logger, s.cfg, urlRepository, urlCacheRepository, codeFilter, lookupLock, auditRepository, passwordAttemptRepository, domainService, policyService, gen, s.pool, s.telemetry)
+	if err := urlService.CatchUpCodeLength(ctx); err != nil {
+		s.logger.Errorf("failed to catch up short code length: %v", err)
+	}
+
+	visitService := service.NewVisitService(s.logger, s.cfg, visitRepository, urlRepository, s.pool, s.telemetry)
+	apiKeyService := service.NewAPIKeyService(s.logger, apiKeyRepository)
+	urlHandler := controller.NewHandler(s.logger, s.cfg, urlService, visitService, s.telemetry)
+	authMiddleware := controller.NewAuthMiddleware(s.logger, apiKeyService, s.telemetry)
+	rateLimit := controller.NewRateLimitMiddleware(s.logger, s.cfg, ratelimit.New(s.redis, "ratelimit"), s.telemetry)
+	resolveDomain := controller.NewDomainMiddleware(s.logger, domainService, s.telemetry).ResolveDomain()
+	createLimit := rateLimit.Limit("create", s.cfg.RateLimit.Create)
+	// a batch is charged per link, so that it does not multiply the budget
+	batchLimit := rateLimit.LimitN("batch", s.cfg.RateLimit.Batch, controller.BatchSize)
+	if s.cfg.RateLimit.Batch.Requests <= 0 {
+		batchLimit = rateLimit.LimitN("create", s.cfg.RateLimit.Create, controller.BatchSize)
+	}
+
+	groupV1 := app.Group("/api/v1", authMiddleware.RequireAPIKey(), resolveDomain)
+	groupV1.POST("/urls/shorten", urlHandler.CreateShortURL(), createLimit)
+	// the body is read whole to count its links, so its size is bounded first
+	batchBodyLimit := middleware.BodyLimit(s.maxBatchBody())
+	groupV1.POST("/urls/shorten/batch", urlHandler.CreateShortURLs(), batchBodyLimit, batchLimit)
+	groupV1.GET("/urls", urlHandler.ListURLs())
+	redirectLimit := rateLimit.Limit("redirect", s.cfg.RateLimit.Redirect)
+	groupV1.GET("/urls/:url", urlHandler.RedirectToLongURL(), redirectLimit)
+	groupV1.PATCH("/urls/:url", urlHandler.UpdateLongURL())
+	groupV1.DELETE("/urls/:url", urlHandler.DeleteURL())
+	groupV1.POST("/urls/:url/disable", urlHandler.DisableURL())
+	groupV1.POST("/urls/:url/enable", urlHandler.EnableURL())
+	groupV1.GET("/urls/:url/info", urlHandler.GetURLInfo())
+	groupV1.GET("/urls/:url/stats", urlHandler.GetURLStats())
+	// The password form of protected links posts without an API key, so it bypasses the group middleware
+	app.POST("/api/v1/urls/:url", urlHandler.RedirectToLongURL(), resolveDomain, redirectLimit)
+	// Compact short links, static routes such as /api take precedence over the code.
+	// Branded domains point here, the Host header tells which domain the code belongs to
+	app.GET("/:url", urlHandler.RedirectToLongURL(), resolveDomain, redirectLimit)
+	app.POST("/:url", urlHandler.RedirectToLongURL(), resolveDomain, redirectLimit)
+}
+
+// newIPExtractor reads the client IP from the X-Forwarded-For header set by the trusted proxies,
+// or else from the connection.
+func (s *Server) newIPExtractor() (echo.IPExtractor, error) {
+	if len(s.cfg.Server.TrustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, cidr := range s.cfg.Server.TrustedProxies {
+		_, ipRange, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
+// defaultMaxBatchBody fits a batch of a thousand typical links.
+const defaultMaxBatchBody = "2M"
+
+// maxBatchBody is the size limit of a batch request body, in the format of the BodyLimit middleware.
+func (s *Server) maxBatchBody() string {
+	if s.cfg.Shortener.MaxBatchBody != "" {
+		return s.cfg.Shortener.MaxBatchBody
+	}
+
+	return defaultMaxBatchBody
+}
+
+// newDestinationRules builds the static destination rules of the configuration, reading the host list files.
+func (s *Server) newDestinationRules() (*urlpolicy.Policy, error) {
+	cfg := s.cfg.Shortener.Policy
+	opts := urlpolicy.Options{
+		Patterns:        cfg.Patterns,
+		BlockPrivateIPs: cfg.BlockPrivateIPs,
+	}
+
+	if cfg.ResolveHosts {
+		opts.Resolver = net.DefaultResolver
+	}
+
+	var err error
+	if cfg.BlocklistFile != "" {
+		if opts.BlockedHosts, err = urlpolicy.LoadHosts(cfg.BlocklistFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.AllowlistFile != "" {
+		if opts.AllowedHosts, err = urlpolicy.LoadHosts(cfg.AllowlistFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return urlpolicy.New(opts)
+}
+
+// snowflakeEpoch keeps snowflake codes short, it must never change once codes were issued.
+var snowflakeEpoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func (s *Server) newGenerator() (service.Generator, error) {
+	cfg := s.cfg.Shortener
+	switch cfg.Strategy {
+	case "", "random":
+		return generator.NewGenerator(cfg.CodeLength), nil
+	case "secure":
+		return generator.NewSecureGenerator(cfg.CodeLength), nil
+	case "counter":
+		seq := repository.NewSequenceRepository(s.db, repository.ShortCodeSequence, s.telemetry)
+		return generator.NewCounterGenerator(seq, cfg.CodeLength), nil
+	case "hashids":
+		seq := repository.NewSequenceRepository(s.db, repository.ShortCodeSequence, s.telemetry)
+		return generator.NewObfuscatedGenerator(seq, cfg.Salt, cfg.CodeLength), nil
+	case "snowflake":
+		return generator.NewSnowflakeGenerator(cfg.NodeID, snowflakeEpoch)
+	default:
+		return nil, fmt.Errorf("unknown short code strategy %q", cfg.Strategy)
+	}
 }
 
 var cmdServer = func(cfg *infra.Config, log *logrus.Logger, postgresDb *gorm.DB, redis *redis.Client) *cobra.Command {
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/domain/service"
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+const (
+	headerAPIKey = "X-API-Key"
+	bearerScheme = "Bearer "
+
+	msgMissingAPIKey = "missing api key"
+	msgInvalidAPIKey = "invalid api key"
+)
+
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, rawKey string) (*model.APIKey, error)
+}
+
+type AuthMiddleware struct {
+	logger        *logrus.Logger
+	authenticator APIKeyAuthenticator
+	tracer        trace.Tracer
+	rejectedCount infra.Counter
+}
+
+func NewAuthMiddleware(logger *logrus.Logger, authenticator APIKeyAuthenticator, telemetry *infra.TelemetryProvider) *AuthMiddleware {
+	tracer := telemetry.TraceProvider.Tracer("authMiddleware")
+	meter := telemetry.MeterProvider.Meter("authMiddleware")
+	return &AuthMiddleware{
+		logger:        logger,
+		authenticator: authenticator,
+		tracer:        tracer,
+		rejectedCount: infra.NewCounter(meter, "auth.rejections"),
+	}
+}
+
+// RequireAPIKey authenticates write requests and attaches the key owner, and whether the key is an admin one,
+// to the request context.
+// Read requests, including redirects, stay public; they are authenticated only when they carry a key,
+// so that endpoints such as the listing can serve the caller's links.
+func (m *AuthMiddleware) RequireAPIKey() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			rawKey := apiKeyFromRequest(c.Request())
+			if rawKey == "" && isSafeMethod(c.Request().Method) {
+				return next(c)
+			}
+
+			ctx, span := m.tracer.Start(c.Request().Context(), "authMiddleware.authenticate")
+			defer span.End()
+			if rawKey == "" {
+				return m.reject(ctx, span, http.StatusUnauthorized, msgMissingAPIKey, errors.New(msgMissingAPIKey))
+			}
+
+			key, err := m.authenticator.Authenticate(ctx, rawKey)
+			if err != nil {
+				if errors.Is(err, service.ErrInvalidAPIKey) {
+					return m.reject(ctx, span, http.StatusUnauthorized, msgInvalidAPIKey, err)
+				}
+
+				return m.reject(ctx, span, http.StatusInternalServerError, msgInternalServerError, err)
+			}
+
+			req := c.Request()
+			authCtx := service.WithOwner(req.Context(), key.Owner)
+			if key.Admin {
+				authCtx = service.WithAdmin(authCtx)
+			}
+
+			c.SetRequest(req.WithContext(authCtx))
+
+			return next(c)
+		}
+	}
+}
+
+func (m *AuthMiddleware) reject(ctx context.Context, span trace.Span, code int, msg string, err error) error {
+	m.logger.Error(err.Error())
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	m.rejectedCount.Inc(ctx)
+
+	return echo.NewHTTPError(code, msg)
+}
+
+// apiKeyFromRequest reads the key from the X-API-Key header, or else from a bearer Authorization header.
+func apiKeyFromRequest(req *http.Request) string {
+	if key := req.Header.Get(headerAPIKey); key != "" {
+		return key
+	}
+
+	authorization := req.Header.Get(echo.HeaderAuthorization)
+	if len(authorization) > len(bearerScheme) && strings.EqualFold(authorization[:len(bearerScheme)], bearerScheme) {
+		return authorization[len(bearerScheme):]
+	}
+
+	return ""
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/miladbarzideh/shortify/internal/domain/controller/mock"
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/domain/service"
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+type AuthMiddlewareTestSuite struct {
+	suite.Suite
+	mockAuthenticator *mock.Authenticator
+	middleware        *AuthMiddleware
+}
+
+func (suite *AuthMiddlewareTestSuite) SetupTest() {
+	suite.mockAuthenticator = new(mock.Authenticator)
+	suite.middleware = NewAuthMiddleware(logrus.New(), suite.mockAuthenticator, infra.NOOPTelemetry)
+}
+
+// ownerHandler answers with the owner attached to the request context.
+func ownerHandler(c echo.Context) error {
+	owner, _ := service.OwnerFromContext(c.Request().Context())
+	return c.String(http.StatusOK, owner)
+}
+
+func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_RequireAPIKey_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		method        string
+		header        string
+		value         string
+		expectedOwner string
+	}{
+		{
+			method:        http.MethodPost,
+			header:        headerAPIKey,
+			value:         "shfy_valid",
+			expectedOwner: "alice",
+		},
+		{
+			method:        http.MethodDelete,
+			header:        echo.HeaderAuthorization,
+			value:         "Bearer shfy_valid",
+			expectedOwner: "alice",
+		},
+		{
+			method: http.MethodGet,
+		},
+		{
+			method:        http.MethodGet,
+			header:        headerAPIKey,
+			value:         "shfy_valid",
+			expectedOwner: "alice",
+		},
+	}
+
+	for _, tc := range testCases {
+		c, rec := newEchoContext(tc.method, "/api/v1/urls/R849E", nil, "R849E")
+		if tc.header != "" {
+			c.Request().Header.Set(tc.header, tc.value)
+		}
+
+		suite.mockAuthenticator.On("Authenticate", testifymock.Anything, "shfy_valid").Return(&model.APIKey{ID: 1, Owner: "alice"}, nil)
+		err := suite.middleware.RequireAPIKey()(ownerHandler)(c)
+
+		require.NoError(err)
+		require.Equal(http.StatusOK, rec.Code)
+		require.Equal(tc.expectedOwner, rec.Body.String())
+	}
+}
+
+func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_RequireAPIKey_Admin() {
+	require := suite.Require()
+	testCases := []struct {
+		key           *model.APIKey
+		expectedAdmin bool
+	}{
+		{key: &model.APIKey{ID: 1, Owner: "alice"}},
+		{key: &model.APIKey{ID: 2, Owner: "trust-and-safety", Admin: true}, expectedAdmin: true},
+	}
+
+	for _, tc := range testCases {
+		c, _ := newEchoContext(http.MethodPost, "/api/v1/urls/R849E/disable", nil, "R849E")
+		c.Request().Header.Set(headerAPIKey, "shfy_valid")
+		suite.mockAuthenticator.On("Authenticate", testifymock.Anything, "shfy_valid").Return(tc.key, nil).Once()
+		admin := false
+		err := suite.middleware.RequireAPIKey()(func(c echo.Context) error {
+			admin = service.IsAdmin(c.Request().Context())
+			return nil
+		})(c)
+
+		require.NoError(err)
+		require.Equal(tc.expectedAdmin, admin)
+	}
+}
+
+func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_RequireAPIKey_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		method       string
+		value        string
+		err          error
+		expectedCode int
+	}{
+		{
+			method:       http.MethodPost,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			method:       http.MethodGet,
+			value:        "shfy_unknown",
+			err:          service.ErrInvalidAPIKey,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			method:       http.MethodPost,
+			value:        "shfy_revoked",
+			err:          service.ErrInvalidAPIKey,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			method:       http.MethodPost,
+			value:        "shfy_valid",
+			err:          errors.New("connection refused"),
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		c, _ := newEchoContext(tc.method, "/api/v1/urls/shorten", model.URLData{URL: "https://github.com"}, "")
+		if tc.value != "" {
+			c.Request().Header.Set(headerAPIKey, tc.value)
+			suite.mockAuthenticator.On("Authenticate", testifymock.Anything, tc.value).Return(nil, tc.err).Once()
+		}
+
+		err := suite.middleware.RequireAPIKey()(ownerHandler)(c)
+
+		require.Error(err)
+		require.Equal(tc.expectedCode, err.(*echo.HTTPError).Code)
+	}
+}
+
+func TestAuthMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthMiddlewareTestSuite))
+}
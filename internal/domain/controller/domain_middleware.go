@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/miladbarzideh/shortify/internal/domain/service"
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+const queryDomain = "domain"
+
+type DomainRegistry interface {
+	IsRegistered(ctx context.Context, host string) (bool, error)
+}
+
+type DomainMiddleware struct {
+	logger   *logrus.Logger
+	registry DomainRegistry
+	tracer   trace.Tracer
+}
+
+func NewDomainMiddleware(logger *logrus.Logger, registry DomainRegistry, telemetry *infra.TelemetryProvider) *DomainMiddleware {
+	tracer := telemetry.TraceProvider.Tracer("domainMiddleware")
+	return &DomainMiddleware{
+		logger:   logger,
+		registry: registry,
+		tracer:   tracer,
+	}
+}
+
+// ResolveDomain attaches the short domain the request is about to its context. An explicit domain query
+// parameter must be registered; otherwise the Host header is used when it is a registered domain,
+// and any other host, such as the one of the API itself, falls back to the default domain.
+func (m *DomainMiddleware) ResolveDomain() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx, span := m.tracer.Start(c.Request().Context(), "domainMiddleware.resolve")
+			defer span.End()
+			host, explicit := c.QueryParam(queryDomain), true
+			if host == "" {
+				host, explicit = c.Request().Host, false
+			}
+
+			host = service.NormalizeHost(host)
+			registered, err := m.registry.IsRegistered(ctx, host)
+			if err != nil {
+				m.logger.Error(err.Error())
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return echo.NewHTTPError(http.StatusInternalServerError, msgInternalServerError)
+			}
+
+			if !registered {
+				if explicit {
+					err = fmt.Errorf("%w: '%s'", service.ErrUnknownDomain, host)
+					m.logger.Error(err.Error())
+					span.RecordError(err)
+					span.SetStatus(codes.Error, err.Error())
+					return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+				}
+
+				host = ""
+			}
+
+			req := c.Request()
+			c.SetRequest(req.WithContext(service.WithDomain(req.Context(), host)))
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/miladbarzideh/shortify/internal/domain/controller/mock"
+	"github.com/miladbarzideh/shortify/internal/domain/service"
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+type DomainMiddlewareTestSuite struct {
+	suite.Suite
+	mockRegistry *mock.DomainRegistry
+	middleware   *DomainMiddleware
+}
+
+func (suite *DomainMiddlewareTestSuite) SetupTest() {
+	suite.mockRegistry = new(mock.DomainRegistry)
+	suite.middleware = NewDomainMiddleware(logrus.New(), suite.mockRegistry, infra.NOOPTelemetry)
+}
+
+// domainHandler answers with the domain attached to the request context.
+func domainHandler(c echo.Context) error {
+	return c.String(http.StatusOK, service.DomainFromContext(c.Request().Context()))
+}
+
+func (suite *DomainMiddlewareTestSuite) TestDomainMiddleware_ResolveDomain_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		name           string
+		endpoint       string
+		host           string
+		expectedDomain string
+	}{
+		{name: "registered host", endpoint: "/R849E", host: "Go.Example.com:443", expectedDomain: "go.example.com"},
+		{name: "unknown host", endpoint: "/R849E", host: "localhost:8513"},
+		{name: "explicit domain", endpoint: "/api/v1/urls/R849E/info?domain=go.example.com", host: "localhost:8513", expectedDomain: "go.example.com"},
+	}
+
+	suite.mockRegistry.On("IsRegistered", testifymock.Anything, "go.example.com").Return(true, nil)
+	suite.mockRegistry.On("IsRegistered", testifymock.Anything, "localhost").Return(false, nil)
+	for _, tc := range testCases {
+		c, rec := newEchoContext(http.MethodGet, tc.endpoint, nil, "R849E")
+		c.Request().Host = tc.host
+
+		err := suite.middleware.ResolveDomain()(domainHandler)(c)
+
+		require.NoError(err, tc.name)
+		require.Equal(http.StatusOK, rec.Code, tc.name)
+		require.Equal(tc.expectedDomain, rec.Body.String(), tc.name)
+	}
+}
+
+func (suite *DomainMiddlewareTestSuite) TestDomainMiddleware_ResolveDomain_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		name         string
+		registered   bool
+		err          error
+		expectedCode int
+	}{
+		{name: "unknown explicit domain", expectedCode: http.StatusBadRequest},
+		{name: "registry failure", err: errors.New("connection refused"), expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		c, _ := newEchoContext(http.MethodGet, "/api/v1/urls/R849E/info?domain=go.example.com", nil, "R849E")
+		suite.mockRegistry.On("IsRegistered", testifymock.Anything, "go.example.com").Return(tc.registered, tc.err).Once()
+
+		err := suite.middleware.ResolveDomain()(domainHandler)(c)
+
+		require.Error(err, tc.name)
+		require.Equal(tc.expectedCode, err.(*echo.HTTPError).Code, tc.name)
+	}
+}
+
+func TestDomainMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(DomainMiddlewareTestSuite))
+}
@@ -0,0 +1,22 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+)
+
+type Authenticator struct {
+	mock.Mock
+}
+
+func (m *Authenticator) Authenticate(ctx context.Context, rawKey string) (*model.APIKey, error) {
+	args := m.Called(ctx, rawKey)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.APIKey), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
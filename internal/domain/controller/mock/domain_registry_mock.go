@@ -0,0 +1,16 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type DomainRegistry struct {
+	mock.Mock
+}
+
+func (m *DomainRegistry) IsRegistered(ctx context.Context, host string) (bool, error) {
+	args := m.Called(ctx, host)
+	return args.Bool(0), args.Error(1)
+}
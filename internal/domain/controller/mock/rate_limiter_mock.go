@@ -0,0 +1,18 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/miladbarzideh/shortify/pkg/ratelimit"
+)
+
+type RateLimiter struct {
+	mock.Mock
+}
+
+func (m *RateLimiter) AllowN(ctx context.Context, key string, limit ratelimit.Limit, n int) (ratelimit.Result, error) {
+	args := m.Called(ctx, key, limit, n)
+	return args.Get(0).(ratelimit.Result), args.Error(1)
+}
@@ -4,18 +4,83 @@ import (
 	"context"
 
 	"github.com/stretchr/testify/mock"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
 )
 
 type Service struct {
 	mock.Mock
 }
 
-func (m *Service) CreateShortURL(ctx context.Context, url string) (string, error) {
-	args := m.Called(ctx, url)
+func (m *Service) CreateShortURL(ctx context.Context, data model.URLData) (string, error) {
+	args := m.Called(ctx, data)
 	return args.String(0), args.Error(1)
 }
 
-func (m *Service) GetLongURL(ctx context.Context, shortCode string) (string, error) {
+func (m *Service) CreateShortURLs(ctx context.Context, domain string, items []model.URLData) ([]model.BatchResult, error) {
+	args := m.Called(ctx, domain, items)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.BatchResult), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *Service) GetLongURL(ctx context.Context, req model.RedirectRequest) (*model.Redirect, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Redirect), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *Service) GetURLInfo(ctx context.Context, shortCode string) (*model.URLInfo, error) {
 	args := m.Called(ctx, shortCode)
-	return args.String(0), args.Error(1)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.URLInfo), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *Service) UpdateLongURL(ctx context.Context, shortCode string, longURL string, actor string) (*model.URLInfo, error) {
+	args := m.Called(ctx, shortCode, longURL, actor)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.URLInfo), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *Service) DeleteURL(ctx context.Context, shortCode string, actor string) error {
+	args := m.Called(ctx, shortCode, actor)
+	return args.Error(0)
+}
+
+func (m *Service) DisableURL(ctx context.Context, shortCode string, reason string, actor string) (*model.URLInfo, error) {
+	args := m.Called(ctx, shortCode, reason, actor)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.URLInfo), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *Service) EnableURL(ctx context.Context, shortCode string, actor string) (*model.URLInfo, error) {
+	args := m.Called(ctx, shortCode, actor)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.URLInfo), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *Service) ListURLs(ctx context.Context, query model.URLListQuery) (*model.URLPage, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.URLPage), args.Error(1)
+	}
+
+	return nil, args.Error(1)
 }
@@ -0,0 +1,26 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+)
+
+type VisitService struct {
+	mock.Mock
+}
+
+func (m *VisitService) RecordVisit(ctx context.Context, shortCode string, referrer string, userAgent string, clientIP string) {
+	m.Called(ctx, shortCode, referrer, userAgent, clientIP)
+}
+
+func (m *VisitService) GetStats(ctx context.Context, shortCode string) (*model.URLStats, error) {
+	args := m.Called(ctx, shortCode)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.URLStats), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/domain/service"
+	"github.com/miladbarzideh/shortify/internal/infra"
+	"github.com/miladbarzideh/shortify/pkg/ratelimit"
+)
+
+const (
+	headerRateLimitLimit     = "X-RateLimit-Limit"
+	headerRateLimitRemaining = "X-RateLimit-Remaining"
+	headerRateLimitReset     = "X-RateLimit-Reset"
+
+	msgTooManyRequests = "too many requests"
+	msgOverBudget      = "request exceeds the rate limit budget"
+)
+
+type RateLimiter interface {
+	AllowN(ctx context.Context, key string, limit ratelimit.Limit, n int) (ratelimit.Result, error)
+}
+
+type RateLimitMiddleware struct {
+	logger        *logrus.Logger
+	cfg           *infra.Config
+	limiter       RateLimiter
+	allowedCount  infra.Counter
+	rejectedCount infra.Counter
+	failedCount   infra.Counter
+}
+
+func NewRateLimitMiddleware(logger *logrus.Logger,
+	cfg *infra.Config,
+	limiter RateLimiter,
+	telemetry *infra.TelemetryProvider,
+) *RateLimitMiddleware {
+	meter := telemetry.MeterProvider.Meter("rateLimitMiddleware")
+	return &RateLimitMiddleware{
+		logger:        logger,
+		cfg:           cfg,
+		limiter:       limiter,
+		allowedCount:  infra.NewCounter(meter, "ratelimit.allowed"),
+		rejectedCount: infra.NewCounter(meter, "ratelimit.rejected"),
+		failedCount:   infra.NewCounter(meter, "ratelimit.failures"),
+	}
+}
+
+// Limit rejects requests over the budget with 429. Budgets are per scope, and per API key owner
+// or else per client IP. When Redis fails the request is let through, so the limiter cannot take
+// the service down.
+func (m *RateLimitMiddleware) Limit(scope string, limit infra.Limit) echo.MiddlewareFunc {
+	return m.LimitN(scope, limit, func(echo.Context) (int, error) { return 1, nil })
+}
+
+// LimitN is Limit for requests that count as several, such as batches; cost tells how many, or fails the request.
+// A request costing more than the whole budget is rejected with 413.
+func (m *RateLimitMiddleware) LimitN(scope string, limit infra.Limit, cost func(c echo.Context) (int, error)) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if !m.cfg.RateLimit.Enabled || limit.Requests <= 0 {
+			return next
+		}
+
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			n, err := cost(c)
+			if err != nil {
+				return err
+			}
+
+			if n > limit.Requests {
+				m.rejectedCount.Inc(ctx)
+				return echo.NewHTTPError(http.StatusRequestEntityTooLarge, msgOverBudget)
+			}
+
+			result, err := m.limiter.AllowN(ctx, scope+":"+clientIdentity(c), ratelimit.Limit{
+				Requests: limit.Requests,
+				Window:   limit.Window,
+			}, n)
+			if err != nil {
+				m.failedCount.Inc(ctx)
+				m.logger.Errorf("failed to check rate limit of '%s'. Error: %v", scope, err)
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set(headerRateLimitLimit, strconv.Itoa(result.Limit))
+			header.Set(headerRateLimitRemaining, strconv.Itoa(result.Remaining))
+			header.Set(headerRateLimitReset, strconv.Itoa(ceilSeconds(result.Reset)))
+			if !result.Allowed {
+				m.rejectedCount.Inc(ctx)
+				header.Set(echo.HeaderRetryAfter, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+				return echo.NewHTTPError(http.StatusTooManyRequests, msgTooManyRequests)
+			}
+
+			m.allowedCount.Inc(ctx)
+
+			return next(c)
+		}
+	}
+}
+
+// clientIdentity is the API key owner of an authenticated request, or else the client IP.
+func clientIdentity(c echo.Context) string {
+	if owner, ok := service.OwnerFromContext(c.Request().Context()); ok {
+		return "owner:" + owner
+	}
+
+	return "ip:" + c.RealIP()
+}
+
+// BatchSize is the cost of a batch request: the number of its URLs. The body is read whole, so the route must
+// bound its size, e.g. with the BodyLimit middleware, whose 413 error is returned. The body is restored for
+// the handler, and a malformed one costs one request, as the handler rejects it.
+func BatchSize(c echo.Context) (int, error) {
+	req := c.Request()
+	body, err := io.ReadAll(req.Body)
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			return 0, httpErr
+		}
+
+		return 1, nil
+	}
+
+	batch := new(model.BatchURLData)
+	if err = json.Unmarshal(body, batch); err != nil {
+		return 1, nil
+	}
+
+	return max(len(batch.URLs), 1), nil
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,218 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/sirupsen/logrus"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/miladbarzideh/shortify/internal/domain/controller/mock"
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/domain/service"
+	"github.com/miladbarzideh/shortify/internal/infra"
+	"github.com/miladbarzideh/shortify/pkg/ratelimit"
+)
+
+type RateLimitMiddlewareTestSuite struct {
+	suite.Suite
+	mockLimiter *mock.RateLimiter
+	cfg         *infra.Config
+	middleware  *RateLimitMiddleware
+}
+
+func (suite *RateLimitMiddlewareTestSuite) SetupTest() {
+	suite.mockLimiter = new(mock.RateLimiter)
+	suite.cfg = &infra.Config{}
+	suite.cfg.RateLimit.Enabled = true
+	suite.middleware = NewRateLimitMiddleware(logrus.New(), suite.cfg, suite.mockLimiter, infra.NOOPTelemetry)
+}
+
+func okHandler(c echo.Context) error {
+	return c.NoContent(http.StatusOK)
+}
+
+func (suite *RateLimitMiddlewareTestSuite) TestRateLimitMiddleware_Limit_Success() {
+	require := suite.Require()
+	limit := infra.Limit{Requests: 10, Window: time.Minute}
+	testCases := []struct {
+		owner       string
+		expectedKey string
+		result      ratelimit.Result
+		err         error
+		headers     map[string]string
+	}{
+		{
+			expectedKey: "create:ip:192.0.2.1",
+			result:      ratelimit.Result{Allowed: true, Limit: 10, Remaining: 9, Reset: 1500 * time.Millisecond},
+			headers: map[string]string{
+				headerRateLimitLimit:     "10",
+				headerRateLimitRemaining: "9",
+				headerRateLimitReset:     "2",
+			},
+		},
+		{
+			owner:       "alice",
+			expectedKey: "create:owner:alice",
+			result:      ratelimit.Result{Allowed: true, Limit: 10, Remaining: 0, Reset: time.Second},
+			headers: map[string]string{
+				headerRateLimitRemaining: "0",
+				headerRateLimitReset:     "1",
+			},
+		},
+		{
+			expectedKey: "create:ip:192.0.2.1",
+			err:         errors.New("connection refused"),
+			headers: map[string]string{
+				headerRateLimitLimit: "",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		c, rec := newEchoContext(http.MethodPost, "/api/v1/urls/shorten", nil, "")
+		if tc.owner != "" {
+			c.SetRequest(c.Request().WithContext(service.WithOwner(c.Request().Context(), tc.owner)))
+		}
+
+		suite.mockLimiter.On("AllowN", testifymock.Anything, tc.expectedKey, ratelimit.Limit{Requests: 10, Window: time.Minute}, 1).
+			Return(tc.result, tc.err).Once()
+		err := suite.middleware.Limit("create", limit)(okHandler)(c)
+
+		require.NoError(err)
+		require.Equal(http.StatusOK, rec.Code)
+		for name, value := range tc.headers {
+			require.Equal(value, rec.Header().Get(name))
+		}
+	}
+}
+
+func (suite *RateLimitMiddlewareTestSuite) TestRateLimitMiddleware_Limit_Disabled_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		enabled bool
+		limit   infra.Limit
+	}{
+		{
+			limit: infra.Limit{Requests: 10, Window: time.Minute},
+		},
+		{
+			enabled: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.cfg.RateLimit.Enabled = tc.enabled
+		c, rec := newEchoContext(http.MethodGet, "/api/v1/urls/R849E", nil, "R849E")
+		err := suite.middleware.Limit("redirect", tc.limit)(okHandler)(c)
+
+		require.NoError(err)
+		require.Equal(http.StatusOK, rec.Code)
+	}
+
+	suite.mockLimiter.AssertNotCalled(suite.T(), "AllowN", testifymock.Anything, testifymock.Anything, testifymock.Anything, testifymock.Anything)
+}
+
+func (suite *RateLimitMiddlewareTestSuite) TestRateLimitMiddleware_Limit_Failure() {
+	require := suite.Require()
+	c, rec := newEchoContext(http.MethodGet, "/api/v1/urls/R849E", nil, "R849E")
+	suite.mockLimiter.On("AllowN", testifymock.Anything, "redirect:ip:192.0.2.1", testifymock.Anything, 1).
+		Return(ratelimit.Result{Limit: 600, Reset: 20 * time.Second, RetryAfter: 2500 * time.Millisecond}, nil).Once()
+	err := suite.middleware.Limit("redirect", infra.Limit{Requests: 600, Window: time.Minute})(okHandler)(c)
+
+	require.Error(err)
+	require.Equal(http.StatusTooManyRequests, err.(*echo.HTTPError).Code)
+	require.Equal("3", rec.Header().Get(echo.HeaderRetryAfter))
+	require.Equal("600", rec.Header().Get(headerRateLimitLimit))
+	require.Equal("0", rec.Header().Get(headerRateLimitRemaining))
+	require.Equal("20", rec.Header().Get(headerRateLimitReset))
+}
+
+func (suite *RateLimitMiddlewareTestSuite) TestRateLimitMiddleware_LimitN_Batch() {
+	require := suite.Require()
+	limit := infra.Limit{Requests: 3, Window: time.Minute}
+	testCases := []struct {
+		body         any
+		expectedCost int
+		expectedCode int
+	}{
+		{
+			body:         model.BatchURLData{URLs: []model.URLData{{URL: "https://github.com"}, {URL: "https://google.com"}}},
+			expectedCost: 2,
+			expectedCode: http.StatusOK,
+		},
+		{
+			body:         "not a batch",
+			expectedCost: 1,
+			expectedCode: http.StatusOK,
+		},
+		{
+			body:         model.BatchURLData{URLs: make([]model.URLData, 4)},
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tc := range testCases {
+		c, _ := newEchoContext(http.MethodPost, "/api/v1/urls/shorten/batch", tc.body, "")
+		if tc.expectedCost > 0 {
+			suite.mockLimiter.On("AllowN", testifymock.Anything, "batch:ip:192.0.2.1", testifymock.Anything, tc.expectedCost).
+				Return(ratelimit.Result{Allowed: true, Limit: 3}, nil).Once()
+		}
+
+		var bound bool
+		err := suite.middleware.LimitN("batch", limit, BatchSize)(func(c echo.Context) error {
+			// the handler still reads the whole body
+			bound = c.Bind(new(model.BatchURLData)) == nil
+			return c.NoContent(http.StatusOK)
+		})(c)
+
+		if tc.expectedCode == http.StatusOK {
+			require.NoError(err)
+			require.Equal(tc.expectedCost == 2, bound)
+		} else {
+			require.Error(err)
+			require.Equal(tc.expectedCode, err.(*echo.HTTPError).Code)
+		}
+	}
+
+	suite.mockLimiter.AssertExpectations(suite.T())
+}
+
+func (suite *RateLimitMiddlewareTestSuite) TestRateLimitMiddleware_LimitN_BodyLimit() {
+	require := suite.Require()
+	limit := infra.Limit{Requests: 1000, Window: time.Minute}
+	body := model.BatchURLData{URLs: make([]model.URLData, 100)}
+	testCases := []struct {
+		name          string
+		contentLength int64
+	}{
+		{name: "declared length", contentLength: 0},
+		{name: "chunked", contentLength: -1},
+	}
+
+	for _, tc := range testCases {
+		c, _ := newEchoContext(http.MethodPost, "/api/v1/urls/shorten/batch", body, "")
+		if tc.contentLength != 0 {
+			c.Request().ContentLength = tc.contentLength
+		}
+
+		handler := suite.middleware.LimitN("batch", limit, BatchSize)(func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+		err := middleware.BodyLimit("1K")(handler)(c)
+
+		require.Error(err, tc.name)
+		require.Equal(http.StatusRequestEntityTooLarge, err.(*echo.HTTPError).Code, tc.name)
+	}
+
+	suite.mockLimiter.AssertNotCalled(suite.T(), "AllowN", testifymock.Anything, testifymock.Anything, testifymock.Anything, testifymock.Anything)
+}
+
+func TestRateLimitMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitMiddlewareTestSuite))
+}
@@ -14,7 +14,6 @@ import (
 	"github.com/miladbarzideh/shortify/internal/domain/model"
 	"github.com/miladbarzideh/shortify/internal/domain/service"
 	"github.com/miladbarzideh/shortify/internal/infra"
-	"github.com/miladbarzideh/shortify/pkg/generator"
 )
 
 const (
@@ -25,32 +24,54 @@ const (
 )
 
 type URLService interface {
-	CreateShortURL(ctx context.Context, url string) (string, error)
-	GetLongURL(ctx context.Context, shortCode string) (string, error)
+	CreateShortURL(ctx context.Context, data model.URLData) (string, error)
+	CreateShortURLs(ctx context.Context, domain string, items []model.URLData) ([]model.BatchResult, error)
+	GetLongURL(ctx context.Context, req model.RedirectRequest) (*model.Redirect, error)
+	GetURLInfo(ctx context.Context, shortCode string) (*model.URLInfo, error)
+	UpdateLongURL(ctx context.Context, shortCode string, longURL string, actor string) (*model.URLInfo, error)
+	DeleteURL(ctx context.Context, shortCode string, actor string) error
+	DisableURL(ctx context.Context, shortCode string, reason string, actor string) (*model.URLInfo, error)
+	EnableURL(ctx context.Context, shortCode string, actor string) (*model.URLInfo, error)
+	ListURLs(ctx context.Context, query model.URLListQuery) (*model.URLPage, error)
+}
+
+type VisitService interface {
+	RecordVisit(ctx context.Context, shortCode string, referrer string, userAgent string, clientIP string)
+	GetStats(ctx context.Context, shortCode string) (*model.URLStats, error)
 }
 
 type Handler struct {
 	logger         *logrus.Logger
 	cfg            *infra.Config
 	service        URLService
+	visitService   VisitService
 	tracer         trace.Tracer
 	getReqCount    infra.Counter
 	createReqCount infra.Counter
+	batchReqCount  infra.Counter
 }
 
-func NewHandler(logger *logrus.Logger, cfg *infra.Config, service URLService, telemetry *infra.TelemetryProvider) *Handler {
+func NewHandler(logger *logrus.Logger,
+	cfg *infra.Config,
+	service URLService,
+	visitService VisitService,
+	telemetry *infra.TelemetryProvider,
+) *Handler {
 	tracer := telemetry.TraceProvider.Tracer("urlHandler")
 	meter := telemetry.MeterProvider.Meter("urlHandler")
 	getReqCount := infra.NewCounter(meter, "url.gets")
 	createReqCount := infra.NewCounter(meter, "url.creates")
+	batchReqCount := infra.NewCounter(meter, "url.batch_creates")
 
 	return &Handler{
 		logger:         logger,
 		cfg:            cfg,
 		service:        service,
+		visitService:   visitService,
 		tracer:         tracer,
 		getReqCount:    getReqCount,
 		createReqCount: createReqCount,
+		batchReqCount:  batchReqCount,
 	}
 }
 
@@ -73,14 +94,26 @@ func (h *Handler) CreateShortURL() echo.HandlerFunc {
 			return echo.NewHTTPError(http.StatusBadRequest, msgInvalidURLError)
 		}
 
-		span.SetAttributes(attribute.String("url", longURL.URL))
-		shortURL, err := h.service.CreateShortURL(ctx, longURL.URL)
+		span.SetAttributes(attribute.String("url", longURL.URL), attribute.String("alias", longURL.Alias))
+		shortURL, err := h.service.CreateShortURL(ctx, *longURL)
 		if err != nil {
 			h.logger.Error(err.Error())
 			span.RecordError(err)
 			span.SetStatus(codes.Error, err.Error())
-			if errors.Is(err, service.ErrMaxRetriesExceeded) {
+			switch {
+			case errors.Is(err, service.ErrMaxRetriesExceeded):
 				return echo.NewHTTPError(http.StatusServiceUnavailable, msgServiceUnavailable)
+			case errors.Is(err, service.ErrInvalidURL),
+				errors.Is(err, service.ErrInvalidAlias),
+				errors.Is(err, service.ErrInvalidExpiry),
+				errors.Is(err, service.ErrInvalidPassword),
+				errors.Is(err, service.ErrInvalidRedirectCode),
+				errors.Is(err, service.ErrUnknownDomain):
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			case errors.Is(err, service.ErrAliasTaken):
+				return echo.NewHTTPError(http.StatusConflict, err.Error())
+			case errors.Is(err, service.ErrDestinationBlocked):
+				return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
 			}
 
 			return echo.NewHTTPError(http.StatusInternalServerError, msgInternalServerError)
@@ -94,32 +127,101 @@ func (h *Handler) CreateShortURL() echo.HandlerFunc {
 	}
 }
 
+func (h *Handler) CreateShortURLs() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx, span := h.tracer.Start(c.Request().Context(), "urlHandler.createBatch")
+		defer span.End()
+		batch := new(model.BatchURLData)
+		if err := c.Bind(batch); err != nil {
+			h.logger.Error(err.Error())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		span.SetAttributes(attribute.Int("size", len(batch.URLs)))
+		results, err := h.service.CreateShortURLs(ctx, batch.Domain, batch.URLs)
+		if err != nil {
+			h.logger.Error(err.Error())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			if errors.Is(err, service.ErrEmptyBatch) ||
+				errors.Is(err, service.ErrBatchTooLarge) ||
+				errors.Is(err, service.ErrUnknownDomain) {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+
+			return echo.NewHTTPError(http.StatusInternalServerError, msgInternalServerError)
+		}
+
+		h.batchReqCount.Inc(ctx)
+
+		return c.JSON(http.StatusOK, &model.BatchResponse{
+			Results: results,
+		})
+	}
+}
+
 func (h *Handler) RedirectToLongURL() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx, span := h.tracer.Start(c.Request().Context(), "urlHandler.redirect")
 		defer span.End()
-		shortCode := c.Param("url")
-		if !generator.IsValidBase62(shortCode) {
-			h.logger.Errorf("%s: %s", msgInvalidShortCodeError, shortCode)
-			span.RecordError(errors.New(msgInvalidShortCodeError))
-			span.SetStatus(codes.Error, msgInvalidShortCodeError)
-			return echo.NewHTTPError(http.StatusBadRequest, msgInvalidShortCodeError)
+		shortCode, err := h.shortCodeParam(c, span)
+		if err != nil {
+			return err
 		}
 
-		longURL, err := h.service.GetLongURL(ctx, shortCode)
+		req := c.Request()
+		password, fromHeader := passwordFromRequest(c)
+		redirect, err := h.service.GetLongURL(ctx, model.RedirectRequest{
+			ShortCode: shortCode,
+			Password:  password,
+			ClientIP:  c.RealIP(),
+		})
 		if err != nil {
 			h.logger.Error(err.Error())
 			span.RecordError(err)
 			span.SetStatus(codes.Error, err.Error())
-			if errors.Is(err, service.ErrURLNotFound) {
+			switch {
+			case errors.Is(err, service.ErrURLNotFound):
 				return echo.NewHTTPError(http.StatusNotFound, err.Error())
+			case errors.Is(err, service.ErrURLExpired), errors.Is(err, service.ErrURLDisabled):
+				return echo.NewHTTPError(http.StatusGone, err.Error())
+			case errors.Is(err, service.ErrTooManyAttempts):
+				return echo.NewHTTPError(http.StatusTooManyRequests, err.Error())
+			case errors.Is(err, service.ErrDestinationBlocked):
+				return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+			case errors.Is(err, service.ErrPasswordRequired), errors.Is(err, service.ErrWrongPassword):
+				if fromHeader {
+					return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+				}
+
+				return h.renderPasswordForm(c, errors.Is(err, service.ErrWrongPassword))
 			}
 
 			return echo.NewHTTPError(http.StatusInternalServerError, msgInternalServerError)
 		}
 
 		h.getReqCount.Inc(ctx)
+		h.visitService.RecordVisit(ctx, shortCode, req.Referer(), req.UserAgent(), c.RealIP())
+		if password != "" {
+			// a cached redirect would skip the password from then on, and the form post must be followed with a GET
+			return c.Redirect(http.StatusSeeOther, redirect.LongURL)
+		}
 
-		return c.Redirect(http.StatusMovedPermanently, longURL)
+		return c.Redirect(redirect.StatusCode, redirect.LongURL)
 	}
 }
+
+// shortCodeParam returns the short code path parameter, or a bad request error if it cannot be a short code.
+func (h *Handler) shortCodeParam(c echo.Context, span trace.Span) (string, error) {
+	shortCode := c.Param("url")
+	if !service.IsValidShortCode(shortCode) {
+		h.logger.Errorf("%s: %s", msgInvalidShortCodeError, shortCode)
+		span.RecordError(errors.New(msgInvalidShortCodeError))
+		span.SetStatus(codes.Error, msgInvalidShortCodeError)
+		return "", echo.NewHTTPError(http.StatusBadRequest, msgInvalidShortCodeError)
+	}
+
+	return shortCode, nil
+}
@@ -20,18 +20,20 @@ import (
 )
 
 const (
-	apiURL = "localhost:8513/api/v1/urls/"
+	shortURLPrefix = "localhost:8513/"
 )
 
 type URLHandlerTestSuite struct {
 	suite.Suite
-	mockService *mock.Service
-	handler     *Handler
+	mockService      *mock.Service
+	mockVisitService *mock.VisitService
+	handler          *Handler
 }
 
 func (suite *URLHandlerTestSuite) SetupTest() {
 	suite.mockService = new(mock.Service)
-	suite.handler = NewHandler(logrus.New(), &infra.Config{}, suite.mockService, infra.NOOPTelemetry)
+	suite.mockVisitService = new(mock.VisitService)
+	suite.handler = NewHandler(logrus.New(), &infra.Config{}, suite.mockService, suite.mockVisitService, infra.NOOPTelemetry)
 }
 
 func (suite *URLHandlerTestSuite) TestURLHandler_CreateShortURL_Success() {
@@ -43,7 +45,7 @@ func (suite *URLHandlerTestSuite) TestURLHandler_CreateShortURL_Success() {
 	}{
 		{
 			input:            model.URLData{URL: "https://www.google.com"},
-			expectedResponse: model.URLData{URL: apiURL + "R849E"},
+			expectedResponse: model.URLData{URL: shortURLPrefix + "R849E"},
 			expectedCode:     http.StatusOK,
 		},
 	}
@@ -83,6 +85,45 @@ func (suite *URLHandlerTestSuite) TestURLHandler_CreateShortURL_Failure() {
 			err:          service.ErrMaxRetriesExceeded,
 			expectedCode: http.StatusServiceUnavailable,
 		},
+		{
+			input:        model.URLData{URL: "https://echo.labstack.com/docs/testing", Alias: "a"},
+			err:          service.ErrInvalidAlias,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			input:        model.URLData{URL: "javascript:alert(1)"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			input:        model.URLData{URL: "https://echo.labstack.com:99999/docs/testing"},
+			err:          service.ErrInvalidURL,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			input:        model.URLData{URL: "https://echo.labstack.com/docs/testing", ExpiresIn: -1},
+			err:          service.ErrInvalidExpiry,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			input:        model.URLData{URL: "https://echo.labstack.com/docs/testing", Password: strings.Repeat("a", 73)},
+			err:          service.ErrInvalidPassword,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			input:        model.URLData{URL: "https://echo.labstack.com/docs/testing", RedirectCode: 303},
+			err:          service.ErrInvalidRedirectCode,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			input:        model.URLData{URL: "https://echo.labstack.com/docs/testing", Alias: "spring-sale"},
+			err:          service.ErrAliasTaken,
+			expectedCode: http.StatusConflict,
+		},
+		{
+			input:        model.URLData{URL: "http://127.0.0.1/admin"},
+			err:          service.ErrDestinationBlocked,
+			expectedCode: http.StatusUnprocessableEntity,
+		},
 		{
 			input:        model.URLData{URL: "https://echo.labstack.com/docs/testing"},
 			err:          gorm.ErrInvalidData,
@@ -105,60 +146,167 @@ func (suite *URLHandlerTestSuite) TestURLHandler_CreateShortURL_Failure() {
 	}
 }
 
+func (suite *URLHandlerTestSuite) TestURLHandler_CreateShortURLs_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		input            model.BatchURLData
+		expectedResponse model.BatchResponse
+	}{
+		{
+			input: model.BatchURLData{URLs: []model.URLData{
+				{URL: "https://www.google.com"},
+				{URL: "javascript:alert(1)"},
+			}},
+			expectedResponse: model.BatchResponse{Results: []model.BatchResult{
+				{URL: "https://www.google.com", ShortURL: shortURLPrefix + "R849E"},
+				{URL: "javascript:alert(1)", Error: "invalid url"},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		c, rec := newEchoContext(http.MethodPost, "/api/v1/urls/shorten/batch", tc.input, "")
+
+		suite.mockService.On("CreateShortURLs", testifymock.Anything, tc.input.Domain, tc.input.URLs).Return(tc.expectedResponse.Results, nil).Once()
+		err := suite.handler.CreateShortURLs()(c)
+
+		require.NoError(err)
+		require.Equal(http.StatusOK, rec.Code)
+		var actual model.BatchResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &actual)
+		require.NoError(err)
+		require.Equal(tc.expectedResponse, actual)
+	}
+}
+
+func (suite *URLHandlerTestSuite) TestURLHandler_CreateShortURLs_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		input        interface{}
+		err          error
+		expectedCode int
+	}{
+		{
+			input:        "{invalid}",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			input:        model.BatchURLData{},
+			err:          service.ErrEmptyBatch,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			input:        model.BatchURLData{URLs: []model.URLData{{URL: "https://www.google.com"}}},
+			err:          service.ErrBatchTooLarge,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			input:        model.BatchURLData{URLs: []model.URLData{{URL: "https://www.google.com"}}},
+			err:          gorm.ErrInvalidDB,
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		c, _ := newEchoContext(http.MethodPost, "/api/v1/urls/shorten/batch", tc.input, "")
+
+		if tc.err != nil {
+			suite.mockService.On("CreateShortURLs", testifymock.Anything, testifymock.Anything, testifymock.Anything).Return(nil, tc.err).Once()
+		}
+
+		err := suite.handler.CreateShortURLs()(c)
+
+		require.Error(err)
+		require.IsType(&echo.HTTPError{}, err)
+		require.Equal(tc.expectedCode, err.(*echo.HTTPError).Code)
+	}
+}
+
 func (suite *URLHandlerTestSuite) TestURLHandler_RedirectToLongURL_Success() {
 	require := suite.Require()
-	expectedCode := http.StatusMovedPermanently
 	testCases := []struct {
-		input       string
-		expectedURL string
+		input        string
+		expectedURL  string
+		expectedCode int
 	}{
 		{
-			input:       "R849E",
-			expectedURL: "https://www.google.com",
+			input:        "R849E",
+			expectedURL:  "https://www.google.com",
+			expectedCode: http.StatusMovedPermanently,
+		},
+		{
+			input:        "L7dRf",
+			expectedURL:  "https://echo.labstack.com/docs/testing",
+			expectedCode: http.StatusFound,
 		},
 		{
-			input:       "L7dRf",
-			expectedURL: "https://echo.labstack.com/docs/testing",
+			input:        "spring-sale",
+			expectedURL:  "https://echo.labstack.com/docs/testing",
+			expectedCode: http.StatusTemporaryRedirect,
 		},
 	}
 
 	for _, tc := range testCases {
 		c, rec := newEchoContext(http.MethodGet, "/api/v1/urls/"+tc.input, nil, tc.input)
 
-		suite.mockService.On("GetLongURL", testifymock.Anything, tc.input).Return(tc.expectedURL, nil)
+		suite.mockService.On("GetLongURL", testifymock.Anything, model.RedirectRequest{ShortCode: tc.input, ClientIP: "192.0.2.1"}).
+			Return(&model.Redirect{LongURL: tc.expectedURL, StatusCode: tc.expectedCode}, nil)
+		suite.mockVisitService.On("RecordVisit", testifymock.Anything, tc.input, testifymock.Anything, testifymock.Anything, testifymock.Anything).Once()
 		err := suite.handler.RedirectToLongURL()(c)
 
 		require.NoError(err)
-		require.Equal(expectedCode, rec.Code)
+		require.Equal(tc.expectedCode, rec.Code)
 		require.Equal(tc.expectedURL, rec.Header().Get("Location"))
 	}
+
+	suite.mockVisitService.AssertNumberOfCalls(suite.T(), "RecordVisit", len(testCases))
 }
 
 func (suite *URLHandlerTestSuite) TestURLHandler_RedirectToLongURL_Failure() {
 	require := suite.Require()
 	testCases := []struct {
 		input        string
+		err          error
 		expectedCode int
 	}{
 		{
 			input:        "R849E",
+			err:          service.ErrURLNotFound,
 			expectedCode: http.StatusNotFound,
 		},
 		{
 			input:        "=;))",
+			err:          service.ErrURLNotFound,
 			expectedCode: http.StatusBadRequest,
 		},
+		{
+			input:        "Xp09a",
+			err:          service.ErrURLExpired,
+			expectedCode: http.StatusGone,
+		},
+		{
+			input:        "Xp09b",
+			err:          &service.DisabledError{Reason: "phishing"},
+			expectedCode: http.StatusGone,
+		},
+		{
+			input:        "Xp09c",
+			err:          service.ErrDestinationBlocked,
+			expectedCode: http.StatusUnprocessableEntity,
+		},
 	}
 
 	for _, tc := range testCases {
 		c, _ := newEchoContext(http.MethodGet, "/api/v1/urls/"+tc.input, nil, tc.input)
 
-		suite.mockService.On("GetLongURL", testifymock.Anything, tc.input).Return("", service.ErrURLNotFound)
+		suite.mockService.On("GetLongURL", testifymock.Anything, model.RedirectRequest{ShortCode: tc.input, ClientIP: "192.0.2.1"}).Return(nil, tc.err)
 		err := suite.handler.RedirectToLongURL()(c)
 
 		require.Error(err)
 		require.Equal(tc.expectedCode, err.(*echo.HTTPError).Code)
 	}
+
+	suite.mockVisitService.AssertNotCalled(suite.T(), "RecordVisit")
 }
 
 func TestURLHandlerTestSuite(t *testing.T) {
@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/domain/service"
+)
+
+func (h *Handler) ListURLs() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx, span := h.tracer.Start(c.Request().Context(), "urlHandler.list")
+		defer span.End()
+		query := new(model.URLListQuery)
+		if err := (&echo.DefaultBinder{}).BindQueryParams(c, query); err != nil {
+			h.logger.Error(err.Error())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		span.SetAttributes(attribute.String("domain", query.Domain), attribute.String("cursor", query.Cursor))
+		page, err := h.service.ListURLs(ctx, *query)
+		if err != nil {
+			h.logger.Error(err.Error())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			switch {
+			case errors.Is(err, service.ErrOwnerRequired):
+				return echo.NewHTTPError(http.StatusUnauthorized, msgMissingAPIKey)
+			case errors.Is(err, service.ErrInvalidListQuery):
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+
+			return echo.NewHTTPError(http.StatusInternalServerError, msgInternalServerError)
+		}
+
+		return c.JSON(http.StatusOK, page)
+	}
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	testifymock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/domain/service"
+)
+
+func (suite *URLHandlerTestSuite) TestURLHandler_ListURLs_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		endpoint      string
+		expectedQuery model.URLListQuery
+		expectedPage  model.URLPage
+	}{
+		{
+			endpoint:      "/api/v1/urls",
+			expectedQuery: model.URLListQuery{},
+			expectedPage:  model.URLPage{Items: []model.URLInfo{}},
+		},
+		{
+			endpoint:      "/api/v1/urls?owner=me&domain=github&sort=created_at&cursor=NDI&limit=10",
+			expectedQuery: model.URLListQuery{Owner: "me", Domain: "github", Sort: "created_at", Cursor: "NDI", Limit: 10},
+			expectedPage: model.URLPage{
+				Items:      []model.URLInfo{{ShortCode: "R849E", LongURL: "https://github.com/", OwnerID: "alice"}},
+				NextCursor: "NDM",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		c, rec := newEchoContext(http.MethodGet, tc.endpoint, nil, "")
+
+		suite.mockService.On("ListURLs", testifymock.Anything, tc.expectedQuery).Return(&tc.expectedPage, nil).Once()
+		err := suite.handler.ListURLs()(c)
+
+		require.NoError(err)
+		require.Equal(http.StatusOK, rec.Code)
+		var actual model.URLPage
+		err = json.Unmarshal(rec.Body.Bytes(), &actual)
+		require.NoError(err)
+		require.Equal(tc.expectedPage, actual)
+	}
+}
+
+func (suite *URLHandlerTestSuite) TestURLHandler_ListURLs_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		endpoint     string
+		err          error
+		expectedCode int
+	}{
+		{
+			endpoint:     "/api/v1/urls?limit=ten",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			endpoint:     "/api/v1/urls?sort=name",
+			err:          service.ErrInvalidListQuery,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			endpoint:     "/api/v1/urls?owner=me",
+			err:          service.ErrOwnerRequired,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			endpoint:     "/api/v1/urls?domain=github",
+			err:          gorm.ErrInvalidDB,
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		c, _ := newEchoContext(http.MethodGet, tc.endpoint, nil, "")
+
+		if tc.err != nil {
+			suite.mockService.On("ListURLs", testifymock.Anything, testifymock.Anything).Return(nil, tc.err).Once()
+		}
+
+		err := suite.handler.ListURLs()(c)
+
+		require.Error(err)
+		require.Equal(tc.expectedCode, err.(*echo.HTTPError).Code)
+	}
+}
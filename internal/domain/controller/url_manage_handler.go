@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/domain/service"
+)
+
+func (h *Handler) GetURLInfo() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx, span := h.tracer.Start(c.Request().Context(), "urlHandler.info")
+		defer span.End()
+		shortCode, err := h.shortCodeParam(c, span)
+		if err != nil {
+			return err
+		}
+
+		info, err := h.service.GetURLInfo(ctx, shortCode)
+		if err != nil {
+			return h.manageError(span, err)
+		}
+
+		return c.JSON(http.StatusOK, info)
+	}
+}
+
+func (h *Handler) UpdateLongURL() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx, span := h.tracer.Start(c.Request().Context(), "urlHandler.update")
+		defer span.End()
+		shortCode, err := h.shortCodeParam(c, span)
+		if err != nil {
+			return err
+		}
+
+		longURL := new(model.URLData)
+		if err = c.Bind(longURL); err != nil {
+			h.logger.Error(err.Error())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		if !longURL.Validate() {
+			h.logger.Error(msgInvalidURLError)
+			span.RecordError(errors.New(msgInvalidURLError))
+			span.SetStatus(codes.Error, msgInvalidURLError)
+			return echo.NewHTTPError(http.StatusBadRequest, msgInvalidURLError)
+		}
+
+		span.SetAttributes(attribute.String("url", longURL.URL))
+		info, err := h.service.UpdateLongURL(ctx, shortCode, longURL.URL, actor(c))
+		if err != nil {
+			return h.manageError(span, err)
+		}
+
+		return c.JSON(http.StatusOK, info)
+	}
+}
+
+func (h *Handler) DeleteURL() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx, span := h.tracer.Start(c.Request().Context(), "urlHandler.delete")
+		defer span.End()
+		shortCode, err := h.shortCodeParam(c, span)
+		if err != nil {
+			return err
+		}
+
+		if err = h.service.DeleteURL(ctx, shortCode, actor(c)); err != nil {
+			return h.manageError(span, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (h *Handler) DisableURL() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx, span := h.tracer.Start(c.Request().Context(), "urlHandler.disable")
+		defer span.End()
+		shortCode, err := h.shortCodeParam(c, span)
+		if err != nil {
+			return err
+		}
+
+		data := new(model.DisableData)
+		if err = c.Bind(data); err != nil {
+			h.logger.Error(err.Error())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		info, err := h.service.DisableURL(ctx, shortCode, data.Reason, actor(c))
+		if err != nil {
+			return h.manageError(span, err)
+		}
+
+		return c.JSON(http.StatusOK, info)
+	}
+}
+
+func (h *Handler) EnableURL() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx, span := h.tracer.Start(c.Request().Context(), "urlHandler.enable")
+		defer span.End()
+		shortCode, err := h.shortCodeParam(c, span)
+		if err != nil {
+			return err
+		}
+
+		info, err := h.service.EnableURL(ctx, shortCode, actor(c))
+		if err != nil {
+			return h.manageError(span, err)
+		}
+
+		return c.JSON(http.StatusOK, info)
+	}
+}
+
+// actor identifies who issued a change, for the audit log: the API key owner, or the client IP
+// for requests that were not authenticated.
+func actor(c echo.Context) string {
+	if owner, ok := service.OwnerFromContext(c.Request().Context()); ok {
+		return owner
+	}
+
+	return c.RealIP()
+}
+
+// manageError records the error of a management endpoint and maps it to an HTTP error.
+func (h *Handler) manageError(span trace.Span, err error) error {
+	h.logger.Error(err.Error())
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	switch {
+	case errors.Is(err, service.ErrURLNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidURL), errors.Is(err, service.ErrMissingReason):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrDestinationBlocked):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, service.ErrAdminRequired):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+
+	return echo.NewHTTPError(http.StatusInternalServerError, msgInternalServerError)
+}
@@ -0,0 +1,319 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	testifymock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/domain/service"
+)
+
+// testActor is the client IP of requests built by httptest.
+const testActor = "192.0.2.1"
+
+func (suite *URLHandlerTestSuite) TestURLHandler_GetURLInfo_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		input        string
+		expectedInfo model.URLInfo
+	}{
+		{
+			input: "R849E",
+			expectedInfo: model.URLInfo{
+				ShortCode:   "R849E",
+				ShortURL:    shortURLPrefix + "R849E",
+				LongURL:     "https://www.google.com/",
+				OriginalURL: "https://www.google.com",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		c, rec := newEchoContext(http.MethodGet, "/api/v1/urls/"+tc.input+"/info", nil, tc.input)
+
+		suite.mockService.On("GetURLInfo", testifymock.Anything, tc.input).Return(&tc.expectedInfo, nil).Once()
+		err := suite.handler.GetURLInfo()(c)
+
+		require.NoError(err)
+		require.Equal(http.StatusOK, rec.Code)
+		var actual model.URLInfo
+		err = json.Unmarshal(rec.Body.Bytes(), &actual)
+		require.NoError(err)
+		require.Equal(tc.expectedInfo, actual)
+	}
+}
+
+func (suite *URLHandlerTestSuite) TestURLHandler_GetURLInfo_Protected() {
+	require := suite.Require()
+	c, rec := newEchoContext(http.MethodGet, "/api/v1/urls/R849E/info", nil, "R849E")
+	suite.mockService.On("GetURLInfo", testifymock.Anything, "R849E").
+		Return(&model.URLInfo{ShortCode: "R849E", ShortURL: shortURLPrefix + "R849E", Protected: true}, nil).Once()
+	err := suite.handler.GetURLInfo()(c)
+
+	require.NoError(err)
+	require.Equal(http.StatusOK, rec.Code)
+	var body map[string]any
+	require.NoError(json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Equal(true, body["protected"])
+	require.NotContains(body, "long_url")
+	require.NotContains(body, "original_url")
+}
+
+func (suite *URLHandlerTestSuite) TestURLHandler_GetURLInfo_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		input        string
+		err          error
+		expectedCode int
+	}{
+		{
+			input:        "=;))",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			input:        "R849E",
+			err:          service.ErrURLNotFound,
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		c, _ := newEchoContext(http.MethodGet, "/api/v1/urls/"+tc.input+"/info", nil, tc.input)
+
+		if tc.err != nil {
+			suite.mockService.On("GetURLInfo", testifymock.Anything, tc.input).Return(nil, tc.err).Once()
+		}
+
+		err := suite.handler.GetURLInfo()(c)
+
+		require.Error(err)
+		require.Equal(tc.expectedCode, err.(*echo.HTTPError).Code)
+	}
+}
+
+func (suite *URLHandlerTestSuite) TestURLHandler_UpdateLongURL_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		shortCode    string
+		input        model.URLData
+		expectedInfo model.URLInfo
+	}{
+		{
+			shortCode:    "R849E",
+			input:        model.URLData{URL: "https://github.com"},
+			expectedInfo: model.URLInfo{ShortCode: "R849E", LongURL: "https://github.com/"},
+		},
+	}
+
+	for _, tc := range testCases {
+		c, rec := newEchoContext(http.MethodPatch, "/api/v1/urls/"+tc.shortCode, tc.input, tc.shortCode)
+
+		suite.mockService.On("UpdateLongURL", testifymock.Anything, tc.shortCode, tc.input.URL, testActor).Return(&tc.expectedInfo, nil).Once()
+		err := suite.handler.UpdateLongURL()(c)
+
+		require.NoError(err)
+		require.Equal(http.StatusOK, rec.Code)
+		var actual model.URLInfo
+		err = json.Unmarshal(rec.Body.Bytes(), &actual)
+		require.NoError(err)
+		require.Equal(tc.expectedInfo, actual)
+	}
+}
+
+func (suite *URLHandlerTestSuite) TestURLHandler_UpdateLongURL_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		shortCode    string
+		input        interface{}
+		err          error
+		expectedCode int
+	}{
+		{
+			shortCode:    "=;))",
+			input:        model.URLData{URL: "https://github.com"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			shortCode:    "R849E",
+			input:        "{invalid}",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			shortCode:    "R849E",
+			input:        model.URLData{URL: "github.com"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			shortCode:    "R849E",
+			input:        model.URLData{URL: "https://github.com"},
+			err:          service.ErrURLNotFound,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			shortCode:    "R849E",
+			input:        model.URLData{URL: "https://evil.com"},
+			err:          service.ErrDestinationBlocked,
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			shortCode:    "R849E",
+			input:        model.URLData{URL: "https://github.com"},
+			err:          gorm.ErrInvalidDB,
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		c, _ := newEchoContext(http.MethodPatch, "/api/v1/urls/"+tc.shortCode, tc.input, tc.shortCode)
+
+		if tc.err != nil {
+			suite.mockService.On("UpdateLongURL", testifymock.Anything, tc.shortCode, testifymock.Anything, testActor).Return(nil, tc.err).Once()
+		}
+
+		err := suite.handler.UpdateLongURL()(c)
+
+		require.Error(err)
+		require.Equal(tc.expectedCode, err.(*echo.HTTPError).Code)
+	}
+}
+
+func (suite *URLHandlerTestSuite) TestURLHandler_DeleteURL_Success() {
+	require := suite.Require()
+	c, rec := newEchoContext(http.MethodDelete, "/api/v1/urls/R849E", nil, "R849E")
+
+	suite.mockService.On("DeleteURL", testifymock.Anything, "R849E", testActor).Return(nil).Once()
+	err := suite.handler.DeleteURL()(c)
+
+	require.NoError(err)
+	require.Equal(http.StatusNoContent, rec.Code)
+}
+
+func (suite *URLHandlerTestSuite) TestURLHandler_DeleteURL_Failure() {
+	require := suite.Require()
+	c, _ := newEchoContext(http.MethodDelete, "/api/v1/urls/R849E", nil, "R849E")
+
+	suite.mockService.On("DeleteURL", testifymock.Anything, "R849E", testActor).Return(service.ErrURLNotFound).Once()
+	err := suite.handler.DeleteURL()(c)
+
+	require.Error(err)
+	require.Equal(http.StatusNotFound, err.(*echo.HTTPError).Code)
+}
+
+func (suite *URLHandlerTestSuite) TestURLHandler_DisableURL_Success() {
+	require := suite.Require()
+	disabledAt := time.Now().UTC().Truncate(time.Second)
+	testCases := []struct {
+		shortCode    string
+		input        model.DisableData
+		expectedInfo model.URLInfo
+	}{
+		{
+			shortCode: "R849E",
+			input:     model.DisableData{Reason: "phishing"},
+			expectedInfo: model.URLInfo{
+				ShortCode:      "R849E",
+				LongURL:        "https://github.com/",
+				DisabledAt:     &disabledAt,
+				DisabledReason: "phishing",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		c, rec := newEchoContext(http.MethodPost, "/api/v1/urls/"+tc.shortCode+"/disable", tc.input, tc.shortCode)
+
+		suite.mockService.On("DisableURL", testifymock.Anything, tc.shortCode, tc.input.Reason, testActor).Return(&tc.expectedInfo, nil).Once()
+		err := suite.handler.DisableURL()(c)
+
+		require.NoError(err)
+		require.Equal(http.StatusOK, rec.Code)
+		var actual model.URLInfo
+		err = json.Unmarshal(rec.Body.Bytes(), &actual)
+		require.NoError(err)
+		require.Equal(tc.expectedInfo, actual)
+	}
+}
+
+func (suite *URLHandlerTestSuite) TestURLHandler_DisableURL_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		shortCode    string
+		input        interface{}
+		err          error
+		expectedCode int
+	}{
+		{
+			shortCode:    "=;))",
+			input:        model.DisableData{Reason: "phishing"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			shortCode:    "R849E",
+			input:        "{invalid}",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			shortCode:    "R849E",
+			input:        model.DisableData{},
+			err:          service.ErrMissingReason,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			shortCode:    "R849F",
+			input:        model.DisableData{Reason: "phishing"},
+			err:          service.ErrURLNotFound,
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		c, _ := newEchoContext(http.MethodPost, "/api/v1/urls/"+tc.shortCode+"/disable", tc.input, tc.shortCode)
+
+		if tc.err != nil {
+			suite.mockService.On("DisableURL", testifymock.Anything, tc.shortCode, testifymock.Anything, testActor).Return(nil, tc.err).Once()
+		}
+
+		err := suite.handler.DisableURL()(c)
+
+		require.Error(err)
+		require.Equal(tc.expectedCode, err.(*echo.HTTPError).Code)
+	}
+}
+
+func (suite *URLHandlerTestSuite) TestURLHandler_EnableURL_Success() {
+	require := suite.Require()
+	expectedInfo := model.URLInfo{ShortCode: "R849E", LongURL: "https://github.com/"}
+	c, rec := newEchoContext(http.MethodPost, "/api/v1/urls/R849E/enable", nil, "R849E")
+
+	suite.mockService.On("EnableURL", testifymock.Anything, "R849E", testActor).Return(&expectedInfo, nil).Once()
+	err := suite.handler.EnableURL()(c)
+
+	require.NoError(err)
+	require.Equal(http.StatusOK, rec.Code)
+}
+
+func (suite *URLHandlerTestSuite) TestURLHandler_EnableURL_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		serviceErr   error
+		expectedCode int
+	}{
+		{serviceErr: service.ErrURLNotFound, expectedCode: http.StatusNotFound},
+		{serviceErr: service.ErrAdminRequired, expectedCode: http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		c, _ := newEchoContext(http.MethodPost, "/api/v1/urls/R849E/enable", nil, "R849E")
+
+		suite.mockService.On("EnableURL", testifymock.Anything, "R849E", testActor).Return(nil, tc.serviceErr).Once()
+		err := suite.handler.EnableURL()(c)
+
+		require.Error(err)
+		require.Equal(tc.expectedCode, err.(*echo.HTTPError).Code)
+	}
+}
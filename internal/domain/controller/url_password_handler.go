@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// headerLinkPassword carries the password of a protected link for API clients.
+const headerLinkPassword = "X-Link-Password"
+
+var passwordForm = template.Must(template.New("password").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Protected link</title>
+</head>
+<body>
+<form method="post">
+<p>This link is password protected.</p>
+{{if .Wrong}}<p role="alert">Wrong password, try again.</p>{{end}}
+<input type="password" name="password" aria-label="Password" autofocus required>
+<button type="submit">Continue</button>
+</form>
+</body>
+</html>
+`))
+
+// passwordFromRequest reads the password of a protected link from the header, or else from the submitted form.
+// It reports whether the header was used, so that API clients get an error instead of the form.
+func passwordFromRequest(c echo.Context) (string, bool) {
+	if password := c.Request().Header.Get(headerLinkPassword); password != "" {
+		return password, true
+	}
+
+	if c.Request().Method == http.MethodPost {
+		return c.FormValue("password"), false
+	}
+
+	return "", false
+}
+
+func (h *Handler) renderPasswordForm(c echo.Context, wrong bool) error {
+	var buf bytes.Buffer
+	if err := passwordForm.Execute(&buf, struct{ Wrong bool }{Wrong: wrong}); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, msgInternalServerError)
+	}
+
+	c.Response().Header().Set("Cache-Control", "no-store")
+
+	return c.HTMLBlob(http.StatusUnauthorized, buf.Bytes())
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	testifymock "github.com/stretchr/testify/mock"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/domain/service"
+)
+
+func (suite *URLHandlerTestSuite) TestURLHandler_RedirectToLongURL_Protected_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		shortCode string
+		header    string
+		form      string
+	}{
+		{shortCode: "prot1", header: "s3cret"},
+		{shortCode: "prot2", form: "s3cret"},
+	}
+
+	for _, tc := range testCases {
+		c, rec := newEchoContext(http.MethodGet, "/api/v1/urls/"+tc.shortCode, nil, tc.shortCode)
+		if tc.header != "" {
+			c.Request().Header.Set(headerLinkPassword, tc.header)
+		} else {
+			req := c.Request()
+			req.Method = http.MethodPost
+			req.Body = io.NopCloser(strings.NewReader(url.Values{"password": {tc.form}}.Encode()))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		}
+
+		suite.mockService.On("GetLongURL", testifymock.Anything, model.RedirectRequest{
+			ShortCode: tc.shortCode,
+			Password:  "s3cret",
+			ClientIP:  "192.0.2.1",
+		}).Return(&model.Redirect{LongURL: "https://www.google.com", StatusCode: http.StatusMovedPermanently}, nil).Once()
+		suite.mockVisitService.On("RecordVisit", testifymock.Anything, tc.shortCode, testifymock.Anything, testifymock.Anything, testifymock.Anything).Once()
+		err := suite.handler.RedirectToLongURL()(c)
+
+		require.NoError(err)
+		require.Equal(http.StatusSeeOther, rec.Code)
+		require.Equal("https://www.google.com", rec.Header().Get("Location"))
+	}
+}
+
+func (suite *URLHandlerTestSuite) TestURLHandler_RedirectToLongURL_Protected_Form() {
+	require := suite.Require()
+	testCases := []struct {
+		shortCode string
+		err       error
+		wrong     bool
+	}{
+		{shortCode: "prot1", err: service.ErrPasswordRequired},
+		{shortCode: "prot2", err: service.ErrWrongPassword, wrong: true},
+	}
+
+	for _, tc := range testCases {
+		c, rec := newEchoContext(http.MethodGet, "/api/v1/urls/"+tc.shortCode, nil, tc.shortCode)
+		suite.mockService.On("GetLongURL", testifymock.Anything, testifymock.MatchedBy(func(req model.RedirectRequest) bool {
+			return req.ShortCode == tc.shortCode
+		})).Return(nil, tc.err).Once()
+		err := suite.handler.RedirectToLongURL()(c)
+
+		require.NoError(err)
+		require.Equal(http.StatusUnauthorized, rec.Code)
+		require.Contains(rec.Header().Get(echo.HeaderContentType), echo.MIMETextHTML)
+		require.Equal("no-store", rec.Header().Get("Cache-Control"))
+		require.Contains(rec.Body.String(), `<form method="post">`)
+		require.Equal(tc.wrong, strings.Contains(rec.Body.String(), "Wrong password"))
+	}
+
+	suite.mockVisitService.AssertNotCalled(suite.T(), "RecordVisit")
+}
+
+func (suite *URLHandlerTestSuite) TestURLHandler_RedirectToLongURL_Protected_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		shortCode    string
+		err          error
+		expectedCode int
+	}{
+		{shortCode: "prot1", err: service.ErrWrongPassword, expectedCode: http.StatusUnauthorized},
+		{shortCode: "prot2", err: service.ErrTooManyAttempts, expectedCode: http.StatusTooManyRequests},
+	}
+
+	for _, tc := range testCases {
+		c, _ := newEchoContext(http.MethodGet, "/api/v1/urls/"+tc.shortCode, nil, tc.shortCode)
+		c.Request().Header.Set(headerLinkPassword, "guess")
+		suite.mockService.On("GetLongURL", testifymock.Anything, testifymock.MatchedBy(func(req model.RedirectRequest) bool {
+			return req.ShortCode == tc.shortCode
+		})).Return(nil, tc.err).Once()
+		err := suite.handler.RedirectToLongURL()(c)
+
+		require.Error(err)
+		require.Equal(tc.expectedCode, err.(*echo.HTTPError).Code)
+	}
+
+	suite.mockVisitService.AssertNotCalled(suite.T(), "RecordVisit")
+}
@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/miladbarzideh/shortify/internal/domain/service"
+)
+
+func (h *Handler) GetURLStats() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx, span := h.tracer.Start(c.Request().Context(), "urlHandler.stats")
+		defer span.End()
+		shortCode, err := h.shortCodeParam(c, span)
+		if err != nil {
+			return err
+		}
+
+		stats, err := h.visitService.GetStats(ctx, shortCode)
+		if err != nil {
+			h.logger.Error(err.Error())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			if errors.Is(err, service.ErrURLNotFound) {
+				return echo.NewHTTPError(http.StatusNotFound, err.Error())
+			}
+
+			return echo.NewHTTPError(http.StatusInternalServerError, msgInternalServerError)
+		}
+
+		return c.JSON(http.StatusOK, stats)
+	}
+}
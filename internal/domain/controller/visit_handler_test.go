@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	testifymock "github.com/stretchr/testify/mock"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/domain/service"
+)
+
+func (suite *URLHandlerTestSuite) TestURLHandler_GetURLStats_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		input         string
+		expectedStats model.URLStats
+	}{
+		{
+			input: "R849E",
+			expectedStats: model.URLStats{
+				ShortCode:      "R849E",
+				TotalClicks:    3,
+				UniqueVisitors: 2,
+				Daily: []model.DailyStats{
+					{Day: "2024-05-10", Clicks: 1},
+					{Day: "2024-05-11", Clicks: 2},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		c, rec := newEchoContext(http.MethodGet, "/api/v1/urls/"+tc.input+"/stats", nil, tc.input)
+
+		suite.mockVisitService.On("GetStats", testifymock.Anything, tc.input).Return(&tc.expectedStats, nil).Once()
+		err := suite.handler.GetURLStats()(c)
+
+		require.NoError(err)
+		require.Equal(http.StatusOK, rec.Code)
+		var actual model.URLStats
+		err = json.Unmarshal(rec.Body.Bytes(), &actual)
+		require.NoError(err)
+		require.Equal(tc.expectedStats, actual)
+	}
+}
+
+func (suite *URLHandlerTestSuite) TestURLHandler_GetURLStats_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		input        string
+		err          error
+		expectedCode int
+	}{
+		{
+			input:        "=;))",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			input:        "R849E",
+			err:          service.ErrURLNotFound,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			input:        "L7dRf",
+			err:          errors.New("connection refused"),
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		c, _ := newEchoContext(http.MethodGet, "/api/v1/urls/"+tc.input+"/stats", nil, tc.input)
+
+		if tc.err != nil {
+			suite.mockVisitService.On("GetStats", testifymock.Anything, tc.input).Return(nil, tc.err).Once()
+		}
+
+		err := suite.handler.GetURLStats()(c)
+
+		require.Error(err)
+		require.IsType(&echo.HTTPError{}, err)
+		require.Equal(tc.expectedCode, err.(*echo.HTTPError).Code)
+	}
+}
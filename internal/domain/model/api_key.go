@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// APIKey authenticates the write endpoints. Only the SHA-256 of the key is stored,
+// the key itself is shown once when it is created.
+type APIKey struct {
+	ID        uint   `gorm:"primaryKey; auto_increment"`
+	Owner     string `gorm:"size:100; index"`
+	Prefix    string `gorm:"size:16"` // First characters of the key, to tell keys apart in listings
+	KeyHash   string `gorm:"size:64; unique"`
+	Admin     bool   // Admin keys may manage the links of every owner, e.g. to take abusive links down
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+)
+
+// Audit actions recorded for changes to a link.
+const (
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionDisable = "disable"
+	AuditActionEnable  = "enable"
+)
+
+// AuditLog records who changed a link and how. Entries are never updated or deleted.
+type AuditLog struct {
+	ID        uint   `gorm:"primaryKey; auto_increment"`
+	Domain    string `gorm:"size:253; not null; default:''"`
+	ShortCode string `gorm:"size:20; index"`
+	Action    string `gorm:"size:20"`
+	Actor     string
+	Details   string
+	CreatedAt time.Time
+}
@@ -0,0 +1,13 @@
+package model
+
+import (
+	"time"
+)
+
+// BlockedHost is a destination host, and its subdomains, that links must not point to, e.g. a phishing site.
+type BlockedHost struct {
+	ID        uint   `gorm:"primaryKey; auto_increment"`
+	Host      string `gorm:"size:253; unique"`
+	Reason    string
+	CreatedAt time.Time
+}
@@ -0,0 +1,13 @@
+package model
+
+import (
+	"time"
+)
+
+// Domain is a branded short domain registered by an operator. Its links redirect from the domain root,
+// and their short codes are unique per domain.
+type Domain struct {
+	ID        uint   `gorm:"primaryKey; auto_increment"`
+	Host      string `gorm:"size:253; unique"` // Lowercase host name without port, e.g. go.acme.io
+	CreatedAt time.Time
+}
@@ -1,27 +1,162 @@
 package model
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"net/url"
+	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
+// ErrCachedMissing is returned by a cache of links for a short code cached as not existing,
+// as opposed to any other error for a code that is not cached.
+var ErrCachedMissing = errors.New("short code cached as missing")
+
 type URL struct {
-	ID        uint `gorm:"primaryKey; auto_increment"`
-	LongURL   string
-	ShortCode string `gorm:"unique; size:20; index'"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID          uint `gorm:"primaryKey; auto_increment"`
+	LongURL     string
+	LongURLHash string `gorm:"size:64; index"`
+	OriginalURL string // URL as submitted, LongURL holds its canonical form
+	// Domain is the branded short domain of the link, empty for the default one. Codes are unique per domain
+	Domain    string `gorm:"size:253; not null; default:''; uniqueIndex:idx_urls_domain_short_code,priority:1"`
+	ShortCode string `gorm:"size:20; uniqueIndex:idx_urls_domain_short_code,priority:2"`
+	OwnerID   string `gorm:"size:100; index"` // Owner of the API key the link was created with
+	ExpiresAt *time.Time
+	// DisabledAt is set when the link is taken down, e.g. for abuse; the row is kept as evidence
+	DisabledAt     *time.Time
+	DisabledReason string
+	PasswordHash   string // bcrypt hash of the password protecting the redirect, empty for public links
+	RedirectCode   int    // HTTP status of the redirect, 0 for the shortener.redirect_code default
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+}
+
+// HashLongURL returns the hex encoded SHA-256 of the long URL, used to look up identical destinations.
+func HashLongURL(longURL string) string {
+	sum := sha256.Sum256([]byte(longURL))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsDisabled reports whether the link has been taken down.
+func (u *URL) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+// IsProtected reports whether a password is required to follow the link.
+func (u *URL) IsProtected() bool {
+	return u.PasswordHash != ""
+}
+
+// IsExpired reports whether the URL has an expiry that is not after now.
+func (u *URL) IsExpired(now time.Time) bool {
+	return u.ExpiresAt != nil && !u.ExpiresAt.After(now)
 }
 
 type URLData struct {
-	URL string `json:"url"`
+	URL       string     `json:"url"`
+	Alias     string     `json:"alias,omitempty"`
+	ExpiresIn int64      `json:"expires_in,omitempty"` // Lifetime in seconds
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// Deduplicate overrides the server-wide shortener.deduplicate setting for this request
+	Deduplicate *bool `json:"deduplicate,omitempty"`
+	// Password is required to follow the link, it is only stored hashed
+	Password string `json:"password,omitempty"`
+	// Domain is a registered short domain to create the link on, the default domain when omitted
+	Domain string `json:"domain,omitempty"`
+	// RedirectCode is 301, 302, 307 or 308, the server default when omitted
+	RedirectCode int `json:"redirect_code,omitempty"`
 }
 
 func (u URLData) Validate() bool {
 	parsedURL, err := url.Parse(u.URL)
-	if err == nil && parsedURL.Scheme != "" && parsedURL.Host != "" {
+	if err == nil && isWebScheme(parsedURL.Scheme) && parsedURL.Host != "" {
 		return true
 	}
 
 	return false
 }
+
+func isWebScheme(scheme string) bool {
+	return strings.EqualFold(scheme, "http") || strings.EqualFold(scheme, "https")
+}
+
+// URLInfo is the public metadata of a short URL. The destination of a protected link is left out
+// but for those who may manage it.
+type URLInfo struct {
+	Domain      string     `json:"domain,omitempty"`
+	ShortCode   string     `json:"short_code"`
+	ShortURL    string     `json:"short_url"`
+	LongURL     string     `json:"long_url,omitempty"`
+	OriginalURL string     `json:"original_url,omitempty"`
+	OwnerID     string     `json:"owner_id,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Protected   bool       `json:"protected,omitempty"`
+	// RedirectCode is the status the link redirects with, resolved from the server default when not set
+	RedirectCode int       `json:"redirect_code"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	DisabledAt     *time.Time `json:"disabled_at,omitempty"`
+	DisabledReason string     `json:"disabled_reason,omitempty"`
+}
+
+// RedirectRequest identifies the link to follow and carries what is needed to unlock a protected one.
+type RedirectRequest struct {
+	ShortCode string
+	Password  string
+	ClientIP  string // Failed password attempts are limited per client
+}
+
+// Redirect is where and how a short link redirects.
+type Redirect struct {
+	LongURL    string
+	StatusCode int
+}
+
+// DisableData is the request body to take a link down.
+type DisableData struct {
+	Reason string `json:"reason"`
+}
+
+type BatchURLData struct {
+	URLs []URLData `json:"urls"`
+	// Domain applies to all URLs of the batch
+	Domain string `json:"domain,omitempty"`
+}
+
+type BatchResult struct {
+	URL      string `json:"url"`
+	ShortURL string `json:"short_url,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type BatchResponse struct {
+	Results []BatchResult `json:"results"`
+}
+
+// URLListQuery is the query of the listing endpoint.
+type URLListQuery struct {
+	Owner  string `query:"owner"`
+	Domain string `query:"domain"` // Substring of the destination host
+	Sort   string `query:"sort"`   // created_at or -created_at (default)
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit"`
+}
+
+// URLFilter selects a page of URLs with keyset pagination on the ID, which grows with the creation time.
+type URLFilter struct {
+	OwnerID   string
+	Domain    string
+	AfterID   uint // Exclusive bound in the sort order, 0 for the first page
+	Ascending bool
+	Limit     int
+}
+
+type URLPage struct {
+	Items      []URLInfo `json:"items"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
@@ -40,6 +40,22 @@ func (suite *URLTestSuite) TestURL_Validate_Success() {
 			input:          "/api/v1/urls",
 			expectedResult: false,
 		},
+		{
+			input:          "HTTPS://google.com",
+			expectedResult: true,
+		},
+		{
+			input:          "javascript:alert(1)",
+			expectedResult: false,
+		},
+		{
+			input:          "file:///etc/passwd",
+			expectedResult: false,
+		},
+		{
+			input:          "ftp://example.com/file",
+			expectedResult: false,
+		},
 	}
 
 	for _, tc := range testCases {
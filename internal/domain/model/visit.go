@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+type Visit struct {
+	ID        uint      `gorm:"primaryKey; auto_increment"`
+	Domain    string    `gorm:"size:253; not null; default:''; index:idx_visits_domain_short_code_visited_at,priority:1"`
+	ShortCode string    `gorm:"size:20; index:idx_visits_domain_short_code_visited_at,priority:2"`
+	VisitedAt time.Time `gorm:"index:idx_visits_domain_short_code_visited_at,priority:3"`
+	Referrer  string
+	UserAgent string
+	IPHash    string `gorm:"size:64"`
+	Country   string `gorm:"size:2"`
+}
+
+type URLStats struct {
+	ShortCode      string       `json:"short_code"`
+	TotalClicks    int64        `json:"total_clicks"`
+	UniqueVisitors int64        `json:"unique_visitors"`
+	Daily          []DailyStats `json:"daily"`
+}
+
+type DailyStats struct {
+	Day    string `json:"day"` // YYYY-MM-DD in UTC
+	Clicks int64  `json:"clicks"`
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+type APIKeyRepository struct {
+	logger *logrus.Logger
+	db     *gorm.DB
+	tracer trace.Tracer
+}
+
+func NewAPIKeyRepository(logger *logrus.Logger, db *gorm.DB, telemetry *infra.TelemetryProvider) *APIKeyRepository {
+	tracer := telemetry.TraceProvider.Tracer("apiKeyRepo")
+	return &APIKeyRepository{
+		logger: logger,
+		db:     db,
+		tracer: tracer,
+	}
+}
+
+func (r APIKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+	_, span := r.tracer.Start(ctx, "apiKeyRepo.create")
+	defer span.End()
+
+	return r.db.Create(key).Error
+}
+
+// FindActiveByHash returns the non-revoked key with the given hash.
+func (r APIKeyRepository) FindActiveByHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
+	_, span := r.tracer.Start(ctx, "apiKeyRepo.findActiveByHash")
+	defer span.End()
+	var key model.APIKey
+	result := r.db.Where("key_hash = ? AND revoked_at IS NULL", keyHash).First(&key)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &key, nil
+}
+
+func (r APIKeyRepository) List(ctx context.Context) ([]model.APIKey, error) {
+	_, span := r.tracer.Start(ctx, "apiKeyRepo.list")
+	defer span.End()
+	var keys []model.APIKey
+	result := r.db.Order("id").Find(&keys)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return keys, nil
+}
+
+// Revoke marks the key as revoked; revoking an already revoked key is a not found error.
+func (r APIKeyRepository) Revoke(ctx context.Context, id uint) error {
+	_, span := r.tracer.Start(ctx, "apiKeyRepo.revoke")
+	defer span.End()
+	result := r.db.Model(&model.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+type APIKeyRepositoryTestSuite struct {
+	suite.Suite
+	repo *APIKeyRepository
+	mock sqlmock.Sqlmock
+}
+
+func (suite *APIKeyRepositoryTestSuite) SetupTest() {
+	require := suite.Require()
+	db, mock, err := sqlmock.New()
+	require.NoError(err)
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{TranslateError: true})
+	require.NoError(err)
+	suite.repo = NewAPIKeyRepository(logrus.New(), gormDB, infra.NOOPTelemetry)
+	suite.mock = mock
+}
+
+func (suite *APIKeyRepositoryTestSuite) TestAPIKeyRepository_Create_Success() {
+	require := suite.Require()
+	key := model.APIKey{Owner: "alice", Prefix: "shfy_AbCdEfG", KeyHash: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"}
+	suite.mock.ExpectBegin()
+	insertQuery := `INSERT INTO "api_keys" ("owner","prefix","key_hash","admin","revoked_at","created_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`
+	suite.mock.ExpectQuery(regexp.QuoteMeta(insertQuery)).
+		WithArgs(key.Owner, key.Prefix, key.KeyHash, false, nil, AnyTime{}).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	suite.mock.ExpectCommit()
+	err := suite.repo.Create(context.TODO(), &key)
+
+	require.NoError(err)
+	require.Equal(uint(1), key.ID)
+	require.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *APIKeyRepositoryTestSuite) TestAPIKeyRepository_FindActiveByHash_Success() {
+	require := suite.Require()
+	query := `SELECT * FROM "api_keys" WHERE key_hash = $1 AND revoked_at IS NULL ORDER BY "api_keys"."id" LIMIT $2`
+	rows := sqlmock.NewRows([]string{"id", "owner", "key_hash", "created_at"}).AddRow(1, "alice", "hash", time.Now())
+	suite.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("hash", 1).WillReturnRows(rows)
+	key, err := suite.repo.FindActiveByHash(context.TODO(), "hash")
+
+	require.NoError(err)
+	require.Equal("alice", key.Owner)
+}
+
+func (suite *APIKeyRepositoryTestSuite) TestAPIKeyRepository_FindActiveByHash_Failure() {
+	require := suite.Require()
+	suite.mock.ExpectQuery(`SELECT \* FROM "api_keys" (.+)`).WithArgs("hash", 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	key, err := suite.repo.FindActiveByHash(context.TODO(), "hash")
+
+	require.ErrorIs(err, gorm.ErrRecordNotFound)
+	require.Nil(key)
+}
+
+func (suite *APIKeyRepositoryTestSuite) TestAPIKeyRepository_Revoke_Success() {
+	require := suite.Require()
+	suite.mock.ExpectBegin()
+	updateQuery := `UPDATE "api_keys" SET "revoked_at"=$1 WHERE id = $2 AND revoked_at IS NULL`
+	suite.mock.ExpectExec(regexp.QuoteMeta(updateQuery)).WithArgs(AnyTime{}, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+	err := suite.repo.Revoke(context.TODO(), 1)
+
+	require.NoError(err)
+	require.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *APIKeyRepositoryTestSuite) TestAPIKeyRepository_Revoke_Failure() {
+	require := suite.Require()
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(`UPDATE "api_keys" (.+)`).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectCommit()
+	err := suite.repo.Revoke(context.TODO(), 1)
+
+	require.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func TestAPIKeyRepository(t *testing.T) {
+	suite.Run(t, new(APIKeyRepositoryTestSuite))
+}
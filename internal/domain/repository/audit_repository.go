@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+type AuditRepository struct {
+	logger *logrus.Logger
+	db     *gorm.DB
+	tracer trace.Tracer
+}
+
+func NewAuditRepository(logger *logrus.Logger, db *gorm.DB, telemetry *infra.TelemetryProvider) *AuditRepository {
+	tracer := telemetry.TraceProvider.Tracer("auditRepo")
+	return &AuditRepository{
+		logger: logger,
+		db:     db,
+		tracer: tracer,
+	}
+}
+
+func (r AuditRepository) Create(ctx context.Context, entry *model.AuditLog) error {
+	_, span := r.tracer.Start(ctx, "auditRepo.create")
+	defer span.End()
+
+	return r.db.Create(entry).Error
+}
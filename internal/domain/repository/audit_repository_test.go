@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+type AuditRepositoryTestSuite struct {
+	suite.Suite
+	repo *AuditRepository
+	mock sqlmock.Sqlmock
+}
+
+func (suite *AuditRepositoryTestSuite) SetupTest() {
+	require := suite.Require()
+	db, mock, err := sqlmock.New()
+	require.NoError(err)
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{TranslateError: true})
+	require.NoError(err)
+	suite.repo = NewAuditRepository(logrus.New(), gormDB, infra.NOOPTelemetry)
+	suite.mock = mock
+}
+
+func (suite *AuditRepositoryTestSuite) TestAuditRepository_Create_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		input model.AuditLog
+	}{
+		{
+			input: model.AuditLog{
+				ShortCode: "A5rFt",
+				Action:    model.AuditActionDisable,
+				Actor:     "203.0.113.7",
+				Details:   "phishing",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		suite.mock.ExpectBegin()
+		insertQuery := `INSERT INTO "audit_logs" ("domain","short_code","action","actor","details","created_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`
+		suite.mock.ExpectQuery(regexp.QuoteMeta(insertQuery)).
+			WithArgs(tc.input.Domain, tc.input.ShortCode, tc.input.Action, tc.input.Actor, tc.input.Details, AnyTime{}).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(i + 1))
+		suite.mock.ExpectCommit()
+		err := suite.repo.Create(context.TODO(), &tc.input)
+
+		require.NoError(err)
+		if err = suite.mock.ExpectationsWereMet(); err != nil {
+			suite.T().Errorf("there were unfulfilled expectations: %s", err)
+		}
+	}
+}
+
+func (suite *AuditRepositoryTestSuite) TestAuditRepository_Create_Failure() {
+	require := suite.Require()
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "audit_logs"`)).WillReturnError(errors.New("connection refused"))
+	suite.mock.ExpectRollback()
+	err := suite.repo.Create(context.TODO(), &model.AuditLog{ShortCode: "A5rFt", Action: model.AuditActionDelete})
+
+	require.Error(err)
+}
+
+func TestAuditRepository(t *testing.T) {
+	suite.Run(t, new(AuditRepositoryTestSuite))
+}
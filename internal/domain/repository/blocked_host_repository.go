@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+type BlockedHostRepository struct {
+	logger *logrus.Logger
+	db     *gorm.DB
+	tracer trace.Tracer
+}
+
+func NewBlockedHostRepository(logger *logrus.Logger, db *gorm.DB, telemetry *infra.TelemetryProvider) *BlockedHostRepository {
+	tracer := telemetry.TraceProvider.Tracer("blockedHostRepo")
+	return &BlockedHostRepository{
+		logger: logger,
+		db:     db,
+		tracer: tracer,
+	}
+}
+
+func (r BlockedHostRepository) Create(ctx context.Context, host *model.BlockedHost) error {
+	_, span := r.tracer.Start(ctx, "blockedHostRepo.create")
+	defer span.End()
+
+	return r.db.Create(host).Error
+}
+
+func (r BlockedHostRepository) List(ctx context.Context) ([]model.BlockedHost, error) {
+	_, span := r.tracer.Start(ctx, "blockedHostRepo.list")
+	defer span.End()
+	var hosts []model.BlockedHost
+	result := r.db.Order("host").Find(&hosts)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return hosts, nil
+}
+
+func (r BlockedHostRepository) Delete(ctx context.Context, host string) error {
+	_, span := r.tracer.Start(ctx, "blockedHostRepo.delete")
+	defer span.End()
+	result := r.db.Where("host = ?", host).Delete(&model.BlockedHost{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
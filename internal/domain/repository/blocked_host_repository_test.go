@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+type BlockedHostRepositoryTestSuite struct {
+	suite.Suite
+	repo *BlockedHostRepository
+	mock sqlmock.Sqlmock
+}
+
+func (suite *BlockedHostRepositoryTestSuite) SetupTest() {
+	require := suite.Require()
+	db, mock, err := sqlmock.New()
+	require.NoError(err)
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{TranslateError: true})
+	require.NoError(err)
+	suite.repo = NewBlockedHostRepository(logrus.New(), gormDB, infra.NOOPTelemetry)
+	suite.mock = mock
+}
+
+func (suite *BlockedHostRepositoryTestSuite) TestBlockedHostRepository_Create_Success() {
+	require := suite.Require()
+	host := model.BlockedHost{Host: "evil.com", Reason: "phishing"}
+	suite.mock.ExpectBegin()
+	insertQuery := `INSERT INTO "blocked_hosts" ("host","reason","created_at") VALUES ($1,$2,$3) RETURNING "id"`
+	suite.mock.ExpectQuery(regexp.QuoteMeta(insertQuery)).
+		WithArgs(host.Host, host.Reason, AnyTime{}).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	suite.mock.ExpectCommit()
+	err := suite.repo.Create(context.TODO(), &host)
+
+	require.NoError(err)
+	require.Equal(uint(1), host.ID)
+	require.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *BlockedHostRepositoryTestSuite) TestBlockedHostRepository_List_Success() {
+	require := suite.Require()
+	query := `SELECT * FROM "blocked_hosts" ORDER BY host`
+	rows := sqlmock.NewRows([]string{"id", "host", "reason"}).AddRow(2, "evil.com", "phishing").AddRow(1, "phish.example", "")
+	suite.mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(rows)
+	hosts, err := suite.repo.List(context.TODO())
+
+	require.NoError(err)
+	require.Equal([]model.BlockedHost{{ID: 2, Host: "evil.com", Reason: "phishing"}, {ID: 1, Host: "phish.example"}}, hosts)
+	require.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *BlockedHostRepositoryTestSuite) TestBlockedHostRepository_Delete() {
+	require := suite.Require()
+	testCases := []struct {
+		rowsAffected int64
+		expected     error
+	}{
+		{rowsAffected: 1},
+		{rowsAffected: 0, expected: gorm.ErrRecordNotFound},
+	}
+
+	for _, tc := range testCases {
+		suite.mock.ExpectBegin()
+		query := `DELETE FROM "blocked_hosts" WHERE host = $1`
+		suite.mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs("evil.com").WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+		suite.mock.ExpectCommit()
+		err := suite.repo.Delete(context.TODO(), "evil.com")
+
+		require.ErrorIs(err, tc.expected)
+		require.NoError(suite.mock.ExpectationsWereMet())
+	}
+}
+
+func TestBlockedHostRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(BlockedHostRepositoryTestSuite))
+}
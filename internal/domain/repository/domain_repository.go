@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+type DomainRepository struct {
+	logger *logrus.Logger
+	db     *gorm.DB
+	tracer trace.Tracer
+}
+
+func NewDomainRepository(logger *logrus.Logger, db *gorm.DB, telemetry *infra.TelemetryProvider) *DomainRepository {
+	tracer := telemetry.TraceProvider.Tracer("domainRepo")
+	return &DomainRepository{
+		logger: logger,
+		db:     db,
+		tracer: tracer,
+	}
+}
+
+func (r DomainRepository) Create(ctx context.Context, domain *model.Domain) error {
+	_, span := r.tracer.Start(ctx, "domainRepo.create")
+	defer span.End()
+
+	return r.db.Create(domain).Error
+}
+
+func (r DomainRepository) List(ctx context.Context) ([]model.Domain, error) {
+	_, span := r.tracer.Start(ctx, "domainRepo.list")
+	defer span.End()
+	var domains []model.Domain
+	result := r.db.Order("host").Find(&domains)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return domains, nil
+}
+
+// Delete removes the domain; the links created on it are kept but no longer redirect.
+func (r DomainRepository) Delete(ctx context.Context, host string) error {
+	_, span := r.tracer.Start(ctx, "domainRepo.delete")
+	defer span.End()
+	result := r.db.Where("host = ?", host).Delete(&model.Domain{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+type DomainRepositoryTestSuite struct {
+	suite.Suite
+	repo *DomainRepository
+	mock sqlmock.Sqlmock
+}
+
+func (suite *DomainRepositoryTestSuite) SetupTest() {
+	require := suite.Require()
+	db, mock, err := sqlmock.New()
+	require.NoError(err)
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{TranslateError: true})
+	require.NoError(err)
+	suite.repo = NewDomainRepository(logrus.New(), gormDB, infra.NOOPTelemetry)
+	suite.mock = mock
+}
+
+func (suite *DomainRepositoryTestSuite) TestDomainRepository_Create_Success() {
+	require := suite.Require()
+	domain := model.Domain{Host: "go.acme.io"}
+	suite.mock.ExpectBegin()
+	insertQuery := `INSERT INTO "domains" ("host","created_at") VALUES ($1,$2) RETURNING "id"`
+	suite.mock.ExpectQuery(regexp.QuoteMeta(insertQuery)).
+		WithArgs(domain.Host, AnyTime{}).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	suite.mock.ExpectCommit()
+	err := suite.repo.Create(context.TODO(), &domain)
+
+	require.NoError(err)
+	require.Equal(uint(1), domain.ID)
+	require.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *DomainRepositoryTestSuite) TestDomainRepository_List_Success() {
+	require := suite.Require()
+	query := `SELECT * FROM "domains" ORDER BY host`
+	rows := sqlmock.NewRows([]string{"id", "host"}).AddRow(2, "acme.link").AddRow(1, "go.acme.io")
+	suite.mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(rows)
+	domains, err := suite.repo.List(context.TODO())
+
+	require.NoError(err)
+	require.Equal([]model.Domain{{ID: 2, Host: "acme.link"}, {ID: 1, Host: "go.acme.io"}}, domains)
+	require.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *DomainRepositoryTestSuite) TestDomainRepository_Delete() {
+	require := suite.Require()
+	testCases := []struct {
+		rowsAffected int64
+		expected     error
+	}{
+		{rowsAffected: 1},
+		{rowsAffected: 0, expected: gorm.ErrRecordNotFound},
+	}
+
+	for _, tc := range testCases {
+		suite.mock.ExpectBegin()
+		query := `DELETE FROM "domains" WHERE host = $1`
+		suite.mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs("go.acme.io").WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+		suite.mock.ExpectCommit()
+		err := suite.repo.Delete(context.TODO(), "go.acme.io")
+
+		require.ErrorIs(err, tc.expected)
+		require.NoError(suite.mock.ExpectationsWereMet())
+	}
+}
+
+func TestDomainRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(DomainRepositoryTestSuite))
+}
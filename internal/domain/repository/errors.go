@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// DuplicateShortCodeError is returned when a short code violates the unique constraint.
+// It unwraps to gorm.ErrDuplicatedKey so callers can keep matching on the gorm error.
+type DuplicateShortCodeError struct {
+	ShortCode string
+}
+
+func (e *DuplicateShortCodeError) Error() string {
+	return fmt.Sprintf("short code '%s' already exists", e.ShortCode)
+}
+
+func (e *DuplicateShortCodeError) Unwrap() error {
+	return gorm.ErrDuplicatedKey
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+const lookupLockPrefix = "lookup-lock"
+
+// release deletes the lock only if it is still held with the token, so an expired lock taken over
+// by another instance is left alone.
+//
+// KEYS[1]: lock, ARGV[1]: token
+var release = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// LookupLockRepository holds short lived locks in Redis, so one instance at a time reads a code from the database.
+type LookupLockRepository struct {
+	cache    *redis.Client
+	tracer   trace.Tracer
+	newToken func() (string, error)
+}
+
+func NewLookupLockRepository(redis *redis.Client, telemetry *infra.TelemetryProvider) *LookupLockRepository {
+	tracer := telemetry.TraceProvider.Tracer("lookupLockRepo")
+	return &LookupLockRepository{
+		cache:    redis,
+		tracer:   tracer,
+		newToken: randomToken,
+	}
+}
+
+// Acquire takes the lock of the key for ttl unless it is held, and returns the token to release it with.
+// The ttl must be positive, a lock that never expires would outlive a holder that dies.
+func (r *LookupLockRepository) Acquire(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	_, span := r.tracer.Start(ctx, "lookupLockRepo.acquire")
+	defer span.End()
+	if ttl <= 0 {
+		return "", false, fmt.Errorf("invalid lock ttl %s", ttl)
+	}
+
+	token, err := r.newToken()
+	if err != nil {
+		return "", false, err
+	}
+
+	acquired, err := r.cache.SetNX(ctx, r.buildKey(key), token, ttl).Result()
+	if err != nil || !acquired {
+		return "", false, err
+	}
+
+	return token, true, nil
+}
+
+func (r *LookupLockRepository) Release(ctx context.Context, key string, token string) error {
+	_, span := r.tracer.Start(ctx, "lookupLockRepo.release")
+	defer span.End()
+
+	return release.Run(ctx, r.cache, []string{r.buildKey(key)}, token).Err()
+}
+
+func (r *LookupLockRepository) buildKey(key string) string {
+	return fmt.Sprintf("%s:%s", lookupLockPrefix, key)
+}
+
+func randomToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(token), nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+type LookupLockRepositoryTestSuite struct {
+	suite.Suite
+	repo      *LookupLockRepository
+	cacheMock redismock.ClientMock
+}
+
+func (suite *LookupLockRepositoryTestSuite) SetupTest() {
+	db, mock := redismock.NewClientMock()
+	suite.repo = NewLookupLockRepository(db, infra.NOOPTelemetry)
+	suite.repo.newToken = func() (string, error) { return "token", nil }
+	suite.cacheMock = mock
+}
+
+func (suite *LookupLockRepositoryTestSuite) TestLookupLockRepository_Acquire() {
+	require := suite.Require()
+	testCases := []struct {
+		held          bool
+		err           error
+		expectedToken string
+	}{
+		{expectedToken: "token"},
+		{held: true},
+		{err: errors.New("FAIL")},
+	}
+
+	for _, tc := range testCases {
+		expect := suite.cacheMock.ExpectSetNX("lookup-lock:go.example.com/abcd", "token", 2*time.Second)
+		if tc.err != nil {
+			expect.SetErr(tc.err)
+		} else {
+			expect.SetVal(!tc.held)
+		}
+		token, acquired, err := suite.repo.Acquire(context.TODO(), "go.example.com/abcd", 2*time.Second)
+
+		require.Equal(tc.err, err)
+		require.Equal(tc.expectedToken, token)
+		require.Equal(tc.expectedToken != "", acquired)
+		require.NoError(suite.cacheMock.ExpectationsWereMet())
+	}
+}
+
+func (suite *LookupLockRepositoryTestSuite) TestLookupLockRepository_Acquire_InvalidTTL() {
+	require := suite.Require()
+	for _, ttl := range []time.Duration{0, -time.Second} {
+		token, acquired, err := suite.repo.Acquire(context.TODO(), "abcd", ttl)
+
+		require.Error(err)
+		require.Empty(token)
+		require.False(acquired)
+	}
+
+	require.NoError(suite.cacheMock.ExpectationsWereMet())
+}
+
+func (suite *LookupLockRepositoryTestSuite) TestLookupLockRepository_Release() {
+	require := suite.Require()
+	suite.cacheMock.ExpectEvalSha(release.Hash(), []string{"lookup-lock:abcd"}, "token").SetVal(int64(1))
+
+	err := suite.repo.Release(context.TODO(), "abcd", "token")
+
+	require.NoError(err)
+	require.NoError(suite.cacheMock.ExpectationsWereMet())
+}
+
+func (suite *LookupLockRepositoryTestSuite) TestRandomToken() {
+	require := suite.Require()
+	first, err := randomToken()
+	require.NoError(err)
+	second, _ := randomToken()
+
+	require.Len(first, 32)
+	require.NotEqual(first, second)
+}
+
+func TestLookupLockRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(LookupLockRepositoryTestSuite))
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+const passwordAttemptPrefix = "password-attempts"
+
+// PasswordAttemptRepository counts password attempts in Redis, per link of a domain and client.
+type PasswordAttemptRepository struct {
+	cache  *redis.Client
+	tracer trace.Tracer
+}
+
+func NewPasswordAttemptRepository(redis *redis.Client, telemetry *infra.TelemetryProvider) *PasswordAttemptRepository {
+	tracer := telemetry.TraceProvider.Tracer("passwordAttemptRepo")
+	return &PasswordAttemptRepository{
+		cache:  redis,
+		tracer: tracer,
+	}
+}
+
+// RecordAttempt atomically counts an attempt and returns the attempts so far, this one included.
+// The count expires after the window without new attempts.
+func (r *PasswordAttemptRepository) RecordAttempt(ctx context.Context,
+	domain string,
+	shortCode string,
+	clientIP string,
+	window time.Duration,
+) (int64, error) {
+	_, span := r.tracer.Start(ctx, "passwordAttemptRepo.recordAttempt")
+	defer span.End()
+	key := r.buildKey(domain, shortCode, clientIP)
+	var attempts *redis.IntCmd
+	_, err := r.cache.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		attempts = pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, window)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return attempts.Val(), nil
+}
+
+func (r *PasswordAttemptRepository) Reset(ctx context.Context, domain string, shortCode string, clientIP string) error {
+	_, span := r.tracer.Start(ctx, "passwordAttemptRepo.reset")
+	defer span.End()
+
+	return r.cache.Del(ctx, r.buildKey(domain, shortCode, clientIP)).Err()
+}
+
+func (r *PasswordAttemptRepository) buildKey(domain string, shortCode string, clientIP string) string {
+	if domain == "" {
+		return fmt.Sprintf("%s:%s:%s", passwordAttemptPrefix, shortCode, clientIP)
+	}
+
+	return fmt.Sprintf("%s:%s:%s:%s", passwordAttemptPrefix, domain, shortCode, clientIP)
+}
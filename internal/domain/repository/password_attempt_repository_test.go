@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+type PasswordAttemptRepositoryTestSuite struct {
+	suite.Suite
+	repo      *PasswordAttemptRepository
+	cacheMock redismock.ClientMock
+}
+
+func (suite *PasswordAttemptRepositoryTestSuite) SetupTest() {
+	db, mock := redismock.NewClientMock()
+	suite.repo = NewPasswordAttemptRepository(db, infra.NOOPTelemetry)
+	suite.cacheMock = mock
+}
+
+func (suite *PasswordAttemptRepositoryTestSuite) TestPasswordAttemptRepository_RecordAttempt_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		domain      string
+		expectedKey string
+	}{
+		{expectedKey: "password-attempts:abcd:192.0.2.1"},
+		{domain: "go.example.com", expectedKey: "password-attempts:go.example.com:abcd:192.0.2.1"},
+	}
+
+	for _, tc := range testCases {
+		suite.cacheMock.ExpectTxPipeline()
+		suite.cacheMock.ExpectIncr(tc.expectedKey).SetVal(2)
+		suite.cacheMock.ExpectExpire(tc.expectedKey, 15*time.Minute).SetVal(true)
+		suite.cacheMock.ExpectTxPipelineExec()
+		actual, err := suite.repo.RecordAttempt(context.TODO(), tc.domain, "abcd", "192.0.2.1", 15*time.Minute)
+
+		require.NoError(err)
+		require.Equal(int64(2), actual)
+		require.NoError(suite.cacheMock.ExpectationsWereMet())
+	}
+}
+
+func (suite *PasswordAttemptRepositoryTestSuite) TestPasswordAttemptRepository_RecordAttempt_Failure() {
+	require := suite.Require()
+	suite.cacheMock.ExpectTxPipeline()
+	suite.cacheMock.ExpectIncr("password-attempts:abcd:192.0.2.1").SetErr(errors.New("connection refused"))
+	_, err := suite.repo.RecordAttempt(context.TODO(), "", "abcd", "192.0.2.1", 15*time.Minute)
+
+	require.Error(err)
+	require.NoError(suite.cacheMock.ExpectationsWereMet())
+}
+
+func (suite *PasswordAttemptRepositoryTestSuite) TestPasswordAttemptRepository_Reset_Success() {
+	require := suite.Require()
+	suite.cacheMock.ExpectDel("password-attempts:go.example.com:abcd:192.0.2.1").SetVal(1)
+	err := suite.repo.Reset(context.TODO(), "go.example.com", "abcd", "192.0.2.1")
+
+	require.NoError(err)
+	require.NoError(suite.cacheMock.ExpectationsWereMet())
+}
+
+func TestPasswordAttemptRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(PasswordAttemptRepositoryTestSuite))
+}
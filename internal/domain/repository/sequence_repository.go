@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+// ShortCodeSequence numbers the short codes of the sequence based generators. It is created by the migrate command.
+const ShortCodeSequence = "short_code_seq"
+
+type SequenceRepository struct {
+	db     *gorm.DB
+	name   string
+	tracer trace.Tracer
+}
+
+func NewSequenceRepository(db *gorm.DB, name string, telemetry *infra.TelemetryProvider) *SequenceRepository {
+	tracer := telemetry.TraceProvider.Tracer("sequenceRepo")
+	return &SequenceRepository{
+		db:     db,
+		name:   name,
+		tracer: tracer,
+	}
+}
+
+// Next returns the next value of the Postgres sequence.
+func (r SequenceRepository) Next(ctx context.Context) (uint64, error) {
+	_, span := r.tracer.Start(ctx, "sequenceRepo.next")
+	defer span.End()
+	var next uint64
+	if err := r.db.Raw("SELECT nextval(?)", r.name).Scan(&next).Error; err != nil {
+		return 0, err
+	}
+
+	return next, nil
+}
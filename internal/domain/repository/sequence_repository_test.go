@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+type SequenceRepositoryTestSuite struct {
+	suite.Suite
+	repo *SequenceRepository
+	mock sqlmock.Sqlmock
+}
+
+func (suite *SequenceRepositoryTestSuite) SetupTest() {
+	require := suite.Require()
+	db, mock, err := sqlmock.New()
+	require.NoError(err)
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{TranslateError: true})
+	require.NoError(err)
+	suite.repo = NewSequenceRepository(gormDB, ShortCodeSequence, infra.NOOPTelemetry)
+	suite.mock = mock
+}
+
+func (suite *SequenceRepositoryTestSuite) TestSequenceRepository_Next_Success() {
+	require := suite.Require()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT nextval($1)`)).
+		WithArgs(ShortCodeSequence).
+		WillReturnRows(sqlmock.NewRows([]string{"nextval"}).AddRow(42))
+	next, err := suite.repo.Next(context.TODO())
+
+	require.NoError(err)
+	require.Equal(uint64(42), next)
+}
+
+func (suite *SequenceRepositoryTestSuite) TestSequenceRepository_Next_Failure() {
+	require := suite.Require()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT nextval($1)`)).WillReturnError(errors.New("relation does not exist"))
+	_, err := suite.repo.Next(context.TODO())
+
+	require.Error(err)
+}
+
+func TestSequenceRepository(t *testing.T) {
+	suite.Run(t, new(SequenceRepositoryTestSuite))
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -17,34 +18,87 @@ import (
 const (
 	cachePrefix = "short-url"
 	cacheTTL    = 24 * time.Hour
+	// missingTTL is short, as a code cached as missing by a lookup racing with its creation stays unreachable until then.
+	missingTTL = time.Minute
+	// missingValue marks a code known not to exist; it can not be mistaken for a JSON encoded URL.
+	missingValue = "-"
 )
 
 type CacheRepository struct {
 	logger *logrus.Logger
 	cache  *redis.Client
 	tracer trace.Tracer
+	stats  infra.CacheStats
 }
 
 func NewCacheRepository(logger *logrus.Logger, redis *redis.Client, telemetry *infra.TelemetryProvider) *CacheRepository {
 	tracer := telemetry.TraceProvider.Tracer("urlCacheRepo")
+	meter := telemetry.MeterProvider.Meter("cache")
 	return &CacheRepository{
 		logger: logger,
 		cache:  redis,
 		tracer: tracer,
+		stats:  infra.NewCacheStats(meter, "redis"),
 	}
 }
 
+// Set caches the URL read by a lookup, unless its code is cached already: the lookup may have read the link
+// before it was changed, and must not overwrite the entry of the newer version.
 func (cr *CacheRepository) Set(ctx context.Context, url *model.URL) error {
+	_, err := cr.add(ctx, url)
+	return err
+}
+
+// Replace caches the changed URL over the entry of its previous version.
+func (cr *CacheRepository) Replace(ctx context.Context, url *model.URL) error {
+	_, span := cr.tracer.Start(ctx, "urlCacheRepo.replace")
+	defer span.End()
+	if cacheTTLFor(url, time.Now()) <= 0 {
+		return cr.cache.Del(ctx, cr.buildKeyWithPrefix(url.Domain, url.ShortCode)).Err()
+	}
+
+	_, err := cr.write(ctx, url, false)
+
+	return err
+}
+
+// add caches the URL unless its code is cached already, and reports whether it did.
+func (cr *CacheRepository) add(ctx context.Context, url *model.URL) (bool, error) {
 	_, span := cr.tracer.Start(ctx, "urlCacheRepo.set")
 	defer span.End()
+
+	return cr.write(ctx, url, true)
+}
+
+func (cr *CacheRepository) write(ctx context.Context, url *model.URL, onlyIfAbsent bool) (bool, error) {
+	ttl := cacheTTLFor(url, time.Now())
+	if ttl <= 0 {
+		cr.logger.WithField("shortCode", url.ShortCode).Debug("Skip caching expired URL")
+		return false, nil
+	}
+
 	value, err := json.Marshal(url)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	err = cr.cache.Set(ctx, cr.buildKeyWithPrefix(url.ShortCode), value, cacheTTL).Err()
+	key := cr.buildKeyWithPrefix(url.Domain, url.ShortCode)
+	stored := true
+	start := time.Now()
+	if onlyIfAbsent {
+		stored, err = cr.cache.SetNX(ctx, key, value, ttl).Result()
+	} else {
+		err = cr.cache.Set(ctx, key, value, ttl).Err()
+	}
+	cr.stats.RecordSet(ctx, start)
 	if err != nil {
-		return err
+		cr.stats.SetFailure(ctx)
+		return false, err
+	}
+
+	if !stored {
+		cr.logger.WithField("shortCode", url.ShortCode).Debug("Skip caching URL cached already")
+		return false, nil
 	}
 
 	cr.logger.WithFields(logrus.Fields{
@@ -52,24 +106,40 @@ func (cr *CacheRepository) Set(ctx context.Context, url *model.URL) error {
 		"shortCode":   url.ShortCode,
 	}).Debug("Write URL to cache")
 
-	return nil
+	return true, nil
 }
 
-func (cr *CacheRepository) Get(ctx context.Context, shortCode string) (*model.URL, error) {
+func (cr *CacheRepository) Get(ctx context.Context, domain string, shortCode string) (*model.URL, error) {
 	_, span := cr.tracer.Start(ctx, "urlCacheRepo.get")
 	defer span.End()
 	var url model.URL
-	result, err := cr.cache.Get(ctx, cr.buildKeyWithPrefix(shortCode)).Result()
+	start := time.Now()
+	result, err := cr.cache.Get(ctx, cr.buildKeyWithPrefix(domain, shortCode)).Result()
+	cr.stats.RecordGet(ctx, start)
 	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			cr.stats.Miss(ctx)
+		} else {
+			cr.stats.Error(ctx)
+		}
+
 		cr.logger.Error(err)
 		return nil, err
 	}
 
+	if result == missingValue {
+		cr.stats.Hit(ctx)
+		return nil, model.ErrCachedMissing
+	}
+
 	if err = json.Unmarshal([]byte(result), &url); err != nil {
+		cr.stats.Error(ctx)
 		cr.logger.Error(err)
 		return nil, err
 	}
 
+	cr.stats.Hit(ctx)
+
 	cr.logger.WithFields(logrus.Fields{
 		"originalURL": url.LongURL,
 		"shortCode":   shortCode,
@@ -78,6 +148,68 @@ func (cr *CacheRepository) Get(ctx context.Context, shortCode string) (*model.UR
 	return &url, nil
 }
 
-func (cr *CacheRepository) buildKeyWithPrefix(url string) string {
-	return fmt.Sprintf("%s:%s", cachePrefix, url)
+// Delete invalidates the cached URL, so a removed link is not served from the cache. Its code is cached as missing
+// rather than deleted, so that a lookup that read the link before can not cache it again.
+func (cr *CacheRepository) Delete(ctx context.Context, domain string, shortCode string) error {
+	_, span := cr.tracer.Start(ctx, "urlCacheRepo.delete")
+	defer span.End()
+	if err := cr.cache.Set(ctx, cr.buildKeyWithPrefix(domain, shortCode), missingValue, missingTTL).Err(); err != nil {
+		return err
+	}
+
+	cr.logger.WithField("shortCode", shortCode).Debug("Delete URL from cache")
+
+	return nil
+}
+
+// SetMissing caches that no link has the short code, so lookups of unknown codes do not reach the database.
+func (cr *CacheRepository) SetMissing(ctx context.Context, domain string, shortCode string) error {
+	_, span := cr.tracer.Start(ctx, "urlCacheRepo.setMissing")
+	defer span.End()
+	start := time.Now()
+	err := cr.cache.Set(ctx, cr.buildKeyWithPrefix(domain, shortCode), missingValue, missingTTL).Err()
+	cr.stats.RecordSet(ctx, start)
+	if err != nil {
+		cr.stats.SetFailure(ctx)
+		return err
+	}
+
+	cr.logger.WithField("shortCode", shortCode).Debug("Write missing URL to cache")
+
+	return nil
+}
+
+// DeleteMissing removes the entries cached as missing for newly created short codes.
+func (cr *CacheRepository) DeleteMissing(ctx context.Context, domain string, shortCodes []string) error {
+	_, span := cr.tracer.Start(ctx, "urlCacheRepo.deleteMissing")
+	defer span.End()
+	if len(shortCodes) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(shortCodes))
+	for i, shortCode := range shortCodes {
+		keys[i] = cr.buildKeyWithPrefix(domain, shortCode)
+	}
+
+	return cr.cache.Del(ctx, keys...).Err()
+}
+
+// cacheTTLFor caps the default TTL to the remaining lifetime of the URL,
+// so an expiring link is never served from cache after its expiry.
+func cacheTTLFor(url *model.URL, now time.Time) time.Duration {
+	if url.ExpiresAt == nil {
+		return cacheTTL
+	}
+
+	return min(cacheTTL, url.ExpiresAt.Sub(now))
+}
+
+// buildKeyWithPrefix keys codes of the default domain as before domains were added, so cached entries survive the upgrade.
+func (cr *CacheRepository) buildKeyWithPrefix(domain string, shortCode string) string {
+	if domain == "" {
+		return fmt.Sprintf("%s:%s", cachePrefix, shortCode)
+	}
+
+	return fmt.Sprintf("%s:%s:%s", cachePrefix, domain, shortCode)
 }
@@ -10,6 +10,10 @@ import (
 	"github.com/go-redis/redismock/v9"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"gorm.io/gorm"
 
 	"github.com/miladbarzideh/shortify/internal/domain/model"
 	"github.com/miladbarzideh/shortify/internal/infra"
@@ -45,7 +49,7 @@ func (suite *URLCacheRepositoryTestSuite) TestURLCacheRepository_Set_Success() {
 
 	for _, tc := range testCases {
 		value, _ := json.Marshal(tc.input)
-		suite.cacheMock.ExpectSet(suite.cacheRepo.buildKeyWithPrefix(tc.input.ShortCode), value, 24*time.Hour).SetVal(string(value))
+		suite.cacheMock.ExpectSetNX(suite.cacheRepo.buildKeyWithPrefix(tc.input.Domain, tc.input.ShortCode), value, 24*time.Hour).SetVal(true)
 		err := suite.cacheRepo.Set(context.TODO(), &tc.input)
 
 		require.Nil(err)
@@ -70,13 +74,92 @@ func (suite *URLCacheRepositoryTestSuite) TestURLCacheRepository_Set_Failure() {
 
 	for _, tc := range testCases {
 		value, _ := json.Marshal(tc.input)
-		suite.cacheMock.ExpectSet(suite.cacheRepo.buildKeyWithPrefix(tc.input.ShortCode), value, 24*time.Hour).SetErr(errors.New("FAIL"))
+		suite.cacheMock.ExpectSetNX(suite.cacheRepo.buildKeyWithPrefix(tc.input.Domain, tc.input.ShortCode), value, 24*time.Hour).SetErr(errors.New("FAIL"))
 		err := suite.cacheRepo.Set(context.TODO(), &tc.input)
 
 		require.NotNil(err)
 	}
 }
 
+func (suite *URLCacheRepositoryTestSuite) TestURLCacheRepository_Set_Cached() {
+	require := suite.Require()
+	url := model.URL{ID: 1, LongURL: "https://google.com", ShortCode: "A5rFt"}
+	value, _ := json.Marshal(&url)
+	suite.cacheMock.ExpectSetNX(suite.cacheRepo.buildKeyWithPrefix("", "A5rFt"), value, cacheTTL).SetVal(false)
+	stored, err := suite.cacheRepo.add(context.TODO(), &url)
+
+	require.NoError(err)
+	require.False(stored)
+	require.NoError(suite.cacheMock.ExpectationsWereMet())
+}
+
+func (suite *URLCacheRepositoryTestSuite) TestURLCacheRepository_Replace() {
+	require := suite.Require()
+	expiresAt := time.Now().Add(-time.Minute)
+	url := model.URL{ID: 1, LongURL: "https://google.com", ShortCode: "A5rFt"}
+	value, _ := json.Marshal(&url)
+	suite.cacheMock.ExpectSet(suite.cacheRepo.buildKeyWithPrefix("", "A5rFt"), value, cacheTTL).SetVal("OK")
+	suite.cacheMock.ExpectDel(suite.cacheRepo.buildKeyWithPrefix("", "B6sGu")).SetVal(1)
+
+	require.NoError(suite.cacheRepo.Replace(context.TODO(), &url))
+	require.NoError(suite.cacheRepo.Replace(context.TODO(), &model.URL{ShortCode: "B6sGu", ExpiresAt: &expiresAt}))
+	require.NoError(suite.cacheMock.ExpectationsWereMet())
+}
+
+func (suite *URLCacheRepositoryTestSuite) TestURLCacheRepository_Set_Expired_Success() {
+	require := suite.Require()
+	expiresAt := time.Now().Add(-time.Minute)
+	testCases := []struct {
+		input model.URL
+	}{
+		{
+			input: model.URL{
+				ID:        1,
+				LongURL:   "https://google.com",
+				ShortCode: "A5rFt",
+				ExpiresAt: &expiresAt,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		err := suite.cacheRepo.Set(context.TODO(), &tc.input)
+
+		require.Nil(err)
+		require.NoError(suite.cacheMock.ExpectationsWereMet())
+	}
+}
+
+func (suite *URLCacheRepositoryTestSuite) TestURLCacheRepository_CacheTTLFor_Success() {
+	require := suite.Require()
+	now := time.Now()
+	inOneHour := now.Add(time.Hour)
+	inTwoDays := now.Add(48 * time.Hour)
+	testCases := []struct {
+		input    model.URL
+		expected time.Duration
+	}{
+		{
+			input:    model.URL{ShortCode: "A5rFt"},
+			expected: cacheTTL,
+		},
+		{
+			input:    model.URL{ShortCode: "A5rFt", ExpiresAt: &inOneHour},
+			expected: time.Hour,
+		},
+		{
+			input:    model.URL{ShortCode: "A5rFt", ExpiresAt: &inTwoDays},
+			expected: cacheTTL,
+		},
+	}
+
+	for _, tc := range testCases {
+		actual := cacheTTLFor(&tc.input, now)
+
+		require.Equal(tc.expected, actual)
+	}
+}
+
 func (suite *URLCacheRepositoryTestSuite) TestURLCacheRepository_Get_Success() {
 	require := suite.Require()
 	testCases := []struct {
@@ -95,8 +178,8 @@ func (suite *URLCacheRepositoryTestSuite) TestURLCacheRepository_Get_Success() {
 
 	for _, tc := range testCases {
 		value, _ := json.Marshal(&tc.input)
-		suite.cacheMock.ExpectGet(suite.cacheRepo.buildKeyWithPrefix(tc.input.ShortCode)).SetVal(string(value))
-		actualURL, err := suite.cacheRepo.Get(context.TODO(), tc.input.ShortCode)
+		suite.cacheMock.ExpectGet(suite.cacheRepo.buildKeyWithPrefix(tc.input.Domain, tc.input.ShortCode)).SetVal(string(value))
+		actualURL, err := suite.cacheRepo.Get(context.TODO(), tc.input.Domain, tc.input.ShortCode)
 
 		require.Nil(err)
 		require.Equal(actualURL.LongURL, tc.input.LongURL)
@@ -120,13 +203,142 @@ func (suite *URLCacheRepositoryTestSuite) TestURLCacheRepository_Get_Failure() {
 	}
 
 	for _, tc := range testCases {
-		suite.cacheMock.ExpectGet(suite.cacheRepo.buildKeyWithPrefix(tc.input.ShortCode)).SetErr(errors.New("nil"))
-		_, err := suite.cacheRepo.Get(context.TODO(), tc.input.ShortCode)
+		suite.cacheMock.ExpectGet(suite.cacheRepo.buildKeyWithPrefix(tc.input.Domain, tc.input.ShortCode)).SetErr(errors.New("nil"))
+		_, err := suite.cacheRepo.Get(context.TODO(), tc.input.Domain, tc.input.ShortCode)
 
 		require.NotNil(err)
 	}
 }
 
+func (suite *URLCacheRepositoryTestSuite) TestURLCacheRepository_Get_Missing() {
+	require := suite.Require()
+	suite.cacheMock.ExpectGet(suite.cacheRepo.buildKeyWithPrefix("", "A5rFt")).SetVal(missingValue)
+	url, err := suite.cacheRepo.Get(context.TODO(), "", "A5rFt")
+
+	require.Nil(url)
+	require.ErrorIs(err, model.ErrCachedMissing)
+	require.NotErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (suite *URLCacheRepositoryTestSuite) TestURLCacheRepository_SetMissing() {
+	require := suite.Require()
+	testCases := []struct {
+		err error
+	}{
+		{err: nil},
+		{err: errors.New("FAIL")},
+	}
+
+	for _, tc := range testCases {
+		expect := suite.cacheMock.ExpectSet(suite.cacheRepo.buildKeyWithPrefix("go.example.com", "A5rFt"), missingValue, missingTTL)
+		if tc.err != nil {
+			expect.SetErr(tc.err)
+		} else {
+			expect.SetVal("OK")
+		}
+		err := suite.cacheRepo.SetMissing(context.TODO(), "go.example.com", "A5rFt")
+
+		require.Equal(tc.err, err)
+		require.NoError(suite.cacheMock.ExpectationsWereMet())
+	}
+}
+
+func (suite *URLCacheRepositoryTestSuite) TestURLCacheRepository_DeleteMissing() {
+	require := suite.Require()
+	suite.cacheMock.ExpectDel(suite.cacheRepo.buildKeyWithPrefix("", "A5rFt"), suite.cacheRepo.buildKeyWithPrefix("", "B6sGu")).SetVal(1)
+	err := suite.cacheRepo.DeleteMissing(context.TODO(), "", []string{"A5rFt", "B6sGu"})
+
+	require.NoError(err)
+	require.NoError(suite.cacheRepo.DeleteMissing(context.TODO(), "", nil))
+	require.NoError(suite.cacheMock.ExpectationsWereMet())
+}
+
+func (suite *URLCacheRepositoryTestSuite) TestURLCacheRepository_Delete_Success() {
+	require := suite.Require()
+	suite.cacheMock.ExpectSet(suite.cacheRepo.buildKeyWithPrefix("", "A5rFt"), missingValue, missingTTL).SetVal("OK")
+	err := suite.cacheRepo.Delete(context.TODO(), "", "A5rFt")
+
+	require.NoError(err)
+	require.NoError(suite.cacheMock.ExpectationsWereMet())
+}
+
+func (suite *URLCacheRepositoryTestSuite) TestURLCacheRepository_Delete_Failure() {
+	require := suite.Require()
+	suite.cacheMock.ExpectSet(suite.cacheRepo.buildKeyWithPrefix("", "A5rFt"), missingValue, missingTTL).SetErr(errors.New("FAIL"))
+	err := suite.cacheRepo.Delete(context.TODO(), "", "A5rFt")
+
+	require.Error(err)
+}
+
+func (suite *URLCacheRepositoryTestSuite) TestURLCacheRepository_BuildKeyWithPrefix() {
+	require := suite.Require()
+	testCases := []struct {
+		domain      string
+		shortCode   string
+		expectedKey string
+	}{
+		{shortCode: "A5rFt", expectedKey: "short-url:A5rFt"},
+		{domain: "go.example.com", shortCode: "A5rFt", expectedKey: "short-url:go.example.com:A5rFt"},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(tc.expectedKey, suite.cacheRepo.buildKeyWithPrefix(tc.domain, tc.shortCode))
+	}
+}
+
+func (suite *URLCacheRepositoryTestSuite) TestURLCacheRepository_Stats() {
+	require := suite.Require()
+	reader := sdkmetric.NewManualReader()
+	telemetry := &infra.TelemetryProvider{
+		TraceProvider: infra.NOOPTelemetry.TraceProvider,
+		MeterProvider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	}
+	db, mock := redismock.NewClientMock()
+	cacheRepo := NewCacheRepository(logrus.New(), db, telemetry)
+	url := model.URL{ID: 1, LongURL: "https://google.com", ShortCode: "A5rFt"}
+	value, _ := json.Marshal(&url)
+	mock.ExpectGet("short-url:A5rFt").SetVal(string(value))
+	mock.ExpectGet("short-url:A5rFt").SetVal(missingValue)
+	mock.ExpectGet("short-url:A5rFt").RedisNil()
+	mock.ExpectGet("short-url:A5rFt").SetErr(errors.New("FAIL"))
+	mock.ExpectSetNX("short-url:A5rFt", value, cacheTTL).SetErr(errors.New("FAIL"))
+
+	for i := 0; i < 4; i++ {
+		_, _ = cacheRepo.Get(context.TODO(), "", "A5rFt")
+	}
+	_ = cacheRepo.Set(context.TODO(), &url)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(reader.Collect(context.TODO(), &rm))
+	counts := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					tier, _ := dp.Attributes.Value(attribute.Key("tier"))
+					require.Equal("redis", tier.AsString(), m.Name)
+					counts[m.Name] += dp.Value
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					counts[m.Name] += int64(dp.Count)
+				}
+			}
+		}
+	}
+
+	require.Equal(map[string]int64{
+		"cache.hits":         2,
+		"cache.misses":       1,
+		"cache.errors":       1,
+		"cache.set_failures": 1,
+		"cache.get":          4,
+		"cache.set":          1,
+	}, counts)
+	require.NoError(mock.ExpectationsWereMet())
+}
+
 func TestCacheRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(URLCacheRepositoryTestSuite))
 }
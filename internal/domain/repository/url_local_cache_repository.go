@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/infra"
+	"github.com/miladbarzideh/shortify/pkg/lru"
+)
+
+// invalidationChannel carries the keys evicted by an instance to the local caches of the others.
+const invalidationChannel = "short-url:invalidate"
+
+// LocalCacheRepository keeps the hot links of the Redis cache in memory, so they are served without a round trip.
+// Evictions are broadcast to the other instances through Redis pub/sub.
+type LocalCacheRepository struct {
+	logger *logrus.Logger
+	shared *CacheRepository
+	client *redis.Client
+	local  *lru.Cache[string, *model.URL] // nil for a code cached as missing
+	ttl    time.Duration
+	tracer trace.Tracer
+	stats  infra.CacheStats
+}
+
+func NewLocalCacheRepository(logger *logrus.Logger,
+	shared *CacheRepository,
+	client *redis.Client,
+	cfg infra.LocalCache,
+	telemetry *infra.TelemetryProvider,
+) *LocalCacheRepository {
+	tracer := telemetry.TraceProvider.Tracer("urlLocalCacheRepo")
+	meter := telemetry.MeterProvider.Meter("cache")
+	return &LocalCacheRepository{
+		logger: logger,
+		shared: shared,
+		client: client,
+		local:  lru.New[string, *model.URL](cfg.Size),
+		ttl:    cfg.TTL,
+		tracer: tracer,
+		stats:  infra.NewCacheStats(meter, "local"),
+	}
+}
+
+// Set caches the URL read by a lookup, unless its code is cached in Redis already.
+func (cr *LocalCacheRepository) Set(ctx context.Context, url *model.URL) error {
+	stored, err := cr.shared.add(ctx, url)
+	if err != nil || !stored {
+		return err
+	}
+
+	cr.store(ctx, cr.shared.buildKeyWithPrefix(url.Domain, url.ShortCode), url)
+
+	return nil
+}
+
+// Replace caches the changed URL in Redis and evicts its previous version on every instance.
+func (cr *LocalCacheRepository) Replace(ctx context.Context, url *model.URL) error {
+	if err := cr.shared.Replace(ctx, url); err != nil {
+		return err
+	}
+
+	return cr.evict(ctx, cr.shared.buildKeyWithPrefix(url.Domain, url.ShortCode))
+}
+
+func (cr *LocalCacheRepository) Get(ctx context.Context, domain string, shortCode string) (*model.URL, error) {
+	_, span := cr.tracer.Start(ctx, "urlLocalCacheRepo.get")
+	defer span.End()
+	key := cr.shared.buildKeyWithPrefix(domain, shortCode)
+	start := time.Now()
+	url, ok := cr.local.Get(key)
+	cr.stats.RecordGet(ctx, start)
+	if ok {
+		cr.stats.Hit(ctx)
+		if url == nil {
+			return nil, model.ErrCachedMissing
+		}
+
+		// a copy, so callers can not alter the cached link
+		cached := *url
+		return &cached, nil
+	}
+
+	cr.stats.Miss(ctx)
+	url, err := cr.shared.Get(ctx, domain, shortCode)
+	if err != nil {
+		if errors.Is(err, model.ErrCachedMissing) {
+			cr.local.Set(key, nil, min(cr.ttl, missingTTL))
+		}
+
+		return nil, err
+	}
+
+	cr.store(ctx, key, url)
+
+	return url, nil
+}
+
+// Delete invalidates the cached URL on every instance.
+func (cr *LocalCacheRepository) Delete(ctx context.Context, domain string, shortCode string) error {
+	if err := cr.shared.Delete(ctx, domain, shortCode); err != nil {
+		return err
+	}
+
+	return cr.evict(ctx, cr.shared.buildKeyWithPrefix(domain, shortCode))
+}
+
+func (cr *LocalCacheRepository) SetMissing(ctx context.Context, domain string, shortCode string) error {
+	if err := cr.shared.SetMissing(ctx, domain, shortCode); err != nil {
+		return err
+	}
+
+	cr.local.Set(cr.shared.buildKeyWithPrefix(domain, shortCode), nil, min(cr.ttl, missingTTL))
+
+	return nil
+}
+
+// DeleteMissing removes the entries cached as missing for newly created short codes on every instance.
+func (cr *LocalCacheRepository) DeleteMissing(ctx context.Context, domain string, shortCodes []string) error {
+	if err := cr.shared.DeleteMissing(ctx, domain, shortCodes); err != nil {
+		return err
+	}
+
+	keys := make([]string, len(shortCodes))
+	for i, shortCode := range shortCodes {
+		keys[i] = cr.shared.buildKeyWithPrefix(domain, shortCode)
+	}
+
+	return cr.evict(ctx, keys...)
+}
+
+// Listen evicts the keys invalidated by other instances until ctx is done. Invalidations missed while
+// disconnected from Redis go unnoticed until the entries expire.
+func (cr *LocalCacheRepository) Listen(ctx context.Context) {
+	sub := cr.client.Subscribe(ctx, invalidationChannel)
+	defer sub.Close()
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			cr.local.Delete(message.Payload)
+		}
+	}
+}
+
+// store keeps the URL in memory no longer than it is served from the Redis cache.
+func (cr *LocalCacheRepository) store(ctx context.Context, key string, url *model.URL) {
+	start := time.Now()
+	cached := *url
+	cr.local.Set(key, &cached, min(cr.ttl, cacheTTLFor(url, start)))
+	cr.stats.RecordSet(ctx, start)
+}
+
+// evict deletes the keys from the local cache and publishes them to the other instances.
+func (cr *LocalCacheRepository) evict(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	pipe := cr.client.Pipeline()
+	for _, key := range keys {
+		cr.local.Delete(key)
+		pipe.Publish(ctx, invalidationChannel, key)
+	}
+
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+type URLLocalCacheRepositoryTestSuite struct {
+	suite.Suite
+	cacheRepo *LocalCacheRepository
+	cacheMock redismock.ClientMock
+}
+
+func (suite *URLLocalCacheRepositoryTestSuite) SetupTest() {
+	db, mock := redismock.NewClientMock()
+	shared := NewCacheRepository(logrus.New(), db, infra.NOOPTelemetry)
+	cfg := infra.LocalCache{Size: 10, TTL: 30 * time.Second}
+	suite.cacheRepo = NewLocalCacheRepository(logrus.New(), shared, db, cfg, infra.NOOPTelemetry)
+	suite.cacheMock = mock
+}
+
+func (suite *URLLocalCacheRepositoryTestSuite) TestURLLocalCacheRepository_Get_FromRedisOnce() {
+	require := suite.Require()
+	url := model.URL{ID: 1, LongURL: "https://google.com", ShortCode: "A5rFt"}
+	value, _ := json.Marshal(&url)
+	suite.cacheMock.ExpectGet("short-url:A5rFt").SetVal(string(value))
+
+	for i := 0; i < 2; i++ {
+		actual, err := suite.cacheRepo.Get(context.TODO(), "", "A5rFt")
+
+		require.NoError(err)
+		require.Equal(url.LongURL, actual.LongURL)
+		actual.LongURL = "https://altered.example"
+	}
+
+	require.NoError(suite.cacheMock.ExpectationsWereMet())
+}
+
+func (suite *URLLocalCacheRepositoryTestSuite) TestURLLocalCacheRepository_Get_Missing() {
+	require := suite.Require()
+	suite.cacheMock.ExpectGet("short-url:go.example.com:A5rFt").SetVal(missingValue)
+
+	for i := 0; i < 2; i++ {
+		_, err := suite.cacheRepo.Get(context.TODO(), "go.example.com", "A5rFt")
+
+		require.ErrorIs(err, model.ErrCachedMissing)
+	}
+
+	require.NoError(suite.cacheMock.ExpectationsWereMet())
+}
+
+func (suite *URLLocalCacheRepositoryTestSuite) TestURLLocalCacheRepository_Get_Failure() {
+	require := suite.Require()
+	suite.cacheMock.ExpectGet("short-url:A5rFt").SetErr(errors.New("FAIL"))
+	suite.cacheMock.ExpectGet("short-url:A5rFt").SetErr(errors.New("FAIL"))
+
+	for i := 0; i < 2; i++ {
+		_, err := suite.cacheRepo.Get(context.TODO(), "", "A5rFt")
+
+		require.Error(err)
+	}
+
+	require.NoError(suite.cacheMock.ExpectationsWereMet())
+}
+
+func (suite *URLLocalCacheRepositoryTestSuite) TestURLLocalCacheRepository_Set() {
+	require := suite.Require()
+	url := model.URL{ID: 1, LongURL: "https://google.com", ShortCode: "A5rFt"}
+	value, _ := json.Marshal(&url)
+	suite.cacheMock.ExpectSetNX("short-url:A5rFt", value, cacheTTL).SetVal(true)
+
+	err := suite.cacheRepo.Set(context.TODO(), &url)
+	require.NoError(err)
+
+	actual, err := suite.cacheRepo.Get(context.TODO(), "", "A5rFt")
+	require.NoError(err)
+	require.Equal(url.LongURL, actual.LongURL)
+	require.NoError(suite.cacheMock.ExpectationsWereMet())
+}
+
+func (suite *URLLocalCacheRepositoryTestSuite) TestURLLocalCacheRepository_Set_Cached() {
+	require := suite.Require()
+	url := model.URL{ID: 1, LongURL: "https://google.com", ShortCode: "A5rFt"}
+	value, _ := json.Marshal(&url)
+	suite.cacheMock.ExpectSetNX("short-url:A5rFt", value, cacheTTL).SetVal(false)
+
+	err := suite.cacheRepo.Set(context.TODO(), &url)
+
+	require.NoError(err)
+	require.Zero(suite.cacheRepo.local.Len())
+	require.NoError(suite.cacheMock.ExpectationsWereMet())
+}
+
+func (suite *URLLocalCacheRepositoryTestSuite) TestURLLocalCacheRepository_Replace() {
+	require := suite.Require()
+	suite.cacheRepo.store(context.TODO(), "short-url:A5rFt", &model.URL{LongURL: "https://google.com", ShortCode: "A5rFt"})
+	url := model.URL{ID: 1, LongURL: "https://github.com", ShortCode: "A5rFt"}
+	value, _ := json.Marshal(&url)
+	suite.cacheMock.ExpectSet("short-url:A5rFt", value, cacheTTL).SetVal("OK")
+	suite.cacheMock.ExpectPublish(invalidationChannel, "short-url:A5rFt").SetVal(1)
+
+	err := suite.cacheRepo.Replace(context.TODO(), &url)
+
+	require.NoError(err)
+	_, ok := suite.cacheRepo.local.Get("short-url:A5rFt")
+	require.False(ok)
+	require.NoError(suite.cacheMock.ExpectationsWereMet())
+}
+
+func (suite *URLLocalCacheRepositoryTestSuite) TestURLLocalCacheRepository_Delete() {
+	require := suite.Require()
+	suite.cacheRepo.store(context.TODO(), "short-url:A5rFt", &model.URL{LongURL: "https://google.com", ShortCode: "A5rFt"})
+	suite.cacheMock.ExpectSet("short-url:A5rFt", missingValue, missingTTL).SetVal("OK")
+	suite.cacheMock.ExpectPublish(invalidationChannel, "short-url:A5rFt").SetVal(1)
+
+	err := suite.cacheRepo.Delete(context.TODO(), "", "A5rFt")
+
+	require.NoError(err)
+	_, ok := suite.cacheRepo.local.Get("short-url:A5rFt")
+	require.False(ok)
+	require.NoError(suite.cacheMock.ExpectationsWereMet())
+}
+
+func (suite *URLLocalCacheRepositoryTestSuite) TestURLLocalCacheRepository_Delete_Failure() {
+	require := suite.Require()
+	suite.cacheRepo.store(context.TODO(), "short-url:A5rFt", &model.URL{LongURL: "https://google.com", ShortCode: "A5rFt"})
+	suite.cacheMock.ExpectSet("short-url:A5rFt", missingValue, missingTTL).SetErr(errors.New("FAIL"))
+
+	err := suite.cacheRepo.Delete(context.TODO(), "", "A5rFt")
+
+	require.Error(err)
+	require.NoError(suite.cacheMock.ExpectationsWereMet())
+}
+
+func (suite *URLLocalCacheRepositoryTestSuite) TestURLLocalCacheRepository_SetMissing() {
+	require := suite.Require()
+	suite.cacheMock.ExpectSet("short-url:A5rFt", missingValue, missingTTL).SetVal("OK")
+
+	err := suite.cacheRepo.SetMissing(context.TODO(), "", "A5rFt")
+	require.NoError(err)
+
+	_, err = suite.cacheRepo.Get(context.TODO(), "", "A5rFt")
+	require.ErrorIs(err, model.ErrCachedMissing)
+	require.NoError(suite.cacheMock.ExpectationsWereMet())
+}
+
+func (suite *URLLocalCacheRepositoryTestSuite) TestURLLocalCacheRepository_DeleteMissing() {
+	require := suite.Require()
+	suite.cacheRepo.local.Set("short-url:A5rFt", nil, time.Minute)
+	suite.cacheMock.ExpectDel("short-url:A5rFt", "short-url:B6sGu").SetVal(1)
+	suite.cacheMock.ExpectPublish(invalidationChannel, "short-url:A5rFt").SetVal(1)
+	suite.cacheMock.ExpectPublish(invalidationChannel, "short-url:B6sGu").SetVal(1)
+
+	err := suite.cacheRepo.DeleteMissing(context.TODO(), "", []string{"A5rFt", "B6sGu"})
+
+	require.NoError(err)
+	require.Zero(suite.cacheRepo.local.Len())
+	require.NoError(suite.cacheMock.ExpectationsWereMet())
+}
+
+func TestLocalCacheRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(URLLocalCacheRepositoryTestSuite))
+}
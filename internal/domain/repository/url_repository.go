@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -12,6 +14,9 @@ import (
 	"github.com/miladbarzideh/shortify/internal/infra"
 )
 
+// createBatchSize bounds the rows of one INSERT statement, below the Postgres limit of 65535 bind parameters.
+const createBatchSize = 500
+
 type Repository struct {
 	logger        *logrus.Logger
 	db            *gorm.DB
@@ -39,6 +44,10 @@ func (r Repository) Create(ctx context.Context, url *model.URL) error {
 	start := time.Now()
 	result := r.db.Create(url)
 	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return &DuplicateShortCodeError{ShortCode: url.ShortCode}
+		}
+
 		return result.Error
 	}
 
@@ -47,12 +56,13 @@ func (r Repository) Create(ctx context.Context, url *model.URL) error {
 	return nil
 }
 
-func (r Repository) FindByShortCode(ctx context.Context, shortCode string) (*model.URL, error) {
+// FindByShortCode returns the URL with the short code on the domain, empty for the default domain.
+func (r Repository) FindByShortCode(ctx context.Context, domain string, shortCode string) (*model.URL, error) {
 	start := time.Now()
 	_, span := r.tracer.Start(ctx, "urlRepo.find")
 	defer span.End()
 	var url model.URL
-	result := r.db.Where("short_code = ?", shortCode).First(&url)
+	result := r.db.Where("domain = ? AND short_code = ?", domain, shortCode).First(&url)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -61,3 +71,208 @@ func (r Repository) FindByShortCode(ctx context.Context, shortCode string) (*mod
 
 	return &url, result.Error
 }
+
+// FindByLongURL returns the oldest URL of the owner on the domain pointing to the given long URL that can be shared:
+// permanent, enabled, unprotected and with the default redirect.
+func (r Repository) FindByLongURL(ctx context.Context, ownerID string, domain string, longURL string) (*model.URL, error) {
+	start := time.Now()
+	_, span := r.tracer.Start(ctx, "urlRepo.findByLongURL")
+	defer span.End()
+	var url model.URL
+	result := r.db.Where("long_url_hash = ? AND long_url = ? AND owner_id = ? AND domain = ? AND expires_at IS NULL AND disabled_at IS NULL AND password_hash = '' AND redirect_code = 0",
+		model.HashLongURL(longURL), longURL, ownerID, domain).
+		Order("id").
+		First(&url)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	r.getLatency.Record(ctx, start)
+
+	return &url, nil
+}
+
+// UpdateLongURL writes the destination of the URL identified by its ID, leaving its other columns as they are.
+func (r Repository) UpdateLongURL(ctx context.Context, url *model.URL) error {
+	_, span := r.tracer.Start(ctx, "urlRepo.updateLongURL")
+	defer span.End()
+
+	return r.update(url.ID, map[string]any{
+		"long_url":      url.LongURL,
+		"long_url_hash": url.LongURLHash,
+		"original_url":  url.OriginalURL,
+	})
+}
+
+// UpdateDisabled writes whether, and why, the URL identified by its ID is disabled, leaving its other columns as they are.
+func (r Repository) UpdateDisabled(ctx context.Context, url *model.URL) error {
+	_, span := r.tracer.Start(ctx, "urlRepo.updateDisabled")
+	defer span.End()
+
+	return r.update(url.ID, map[string]any{
+		"disabled_at":     url.DisabledAt,
+		"disabled_reason": url.DisabledReason,
+	})
+}
+
+// update writes only the given columns, so concurrent changes of the other columns are not overwritten
+// with the values read before them.
+func (r Repository) update(id uint, columns map[string]any) error {
+	result := r.db.Model(&model.URL{ID: id}).Updates(columns)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Delete soft deletes the URL: it is no longer served, but the row and its short code are kept.
+func (r Repository) Delete(ctx context.Context, domain string, shortCode string) error {
+	_, span := r.tracer.Start(ctx, "urlRepo.delete")
+	defer span.End()
+	result := r.db.Where("domain = ? AND short_code = ?", domain, shortCode).Delete(&model.URL{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// CreateBatch inserts all URLs in as few statements as possible, within a single transaction.
+func (r Repository) CreateBatch(ctx context.Context, urls []*model.URL) error {
+	start := time.Now()
+	_, span := r.tracer.Start(ctx, "urlRepo.createBatch")
+	defer span.End()
+	result := r.db.CreateInBatches(urls, createBatchSize)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	r.createLatency.Record(ctx, start)
+
+	return nil
+}
+
+// FindExistingShortCodes returns the subset of the given short codes that are already stored on the domain,
+// including deleted ones since their codes are never reused.
+func (r Repository) FindExistingShortCodes(ctx context.Context, domain string, shortCodes []string) ([]string, error) {
+	start := time.Now()
+	_, span := r.tracer.Start(ctx, "urlRepo.findExistingShortCodes")
+	defer span.End()
+	var existing []string
+	result := r.db.Unscoped().Model(&model.URL{}).Where("domain = ? AND short_code IN ?", domain, shortCodes).Pluck("short_code", &existing)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	r.getLatency.Record(ctx, start)
+
+	return existing, nil
+}
+
+// CountByCodeLength counts the stored short codes of the given length, including deleted ones since their codes are never reused.
+func (r Repository) CountByCodeLength(ctx context.Context, length int) (int64, error) {
+	start := time.Now()
+	_, span := r.tracer.Start(ctx, "urlRepo.countByCodeLength")
+	defer span.End()
+	var count int64
+	result := r.db.Unscoped().Model(&model.URL{}).Where("length(short_code) = ?", length).Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	r.getLatency.Record(ctx, start)
+
+	return count, nil
+}
+
+// ListShortCodes returns the domain and short code of up to limit URLs with an ID after afterID, in ID order.
+func (r Repository) ListShortCodes(ctx context.Context, afterID uint, limit int) ([]model.URL, error) {
+	start := time.Now()
+	_, span := r.tracer.Start(ctx, "urlRepo.listShortCodes")
+	defer span.End()
+	var urls []model.URL
+	result := r.db.Select("id", "domain", "short_code").Where("id > ?", afterID).Order("id").Limit(limit).Find(&urls)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	r.getLatency.Record(ctx, start)
+
+	return urls, nil
+}
+
+// FindByLongURLs returns the URLs of the owner on the domain pointing to any of the given long URLs that can be shared,
+// as FindByLongURL, oldest first.
+func (r Repository) FindByLongURLs(ctx context.Context, ownerID string, domain string, longURLs []string) ([]model.URL, error) {
+	start := time.Now()
+	_, span := r.tracer.Start(ctx, "urlRepo.findByLongURLs")
+	defer span.End()
+	hashes := make([]string, len(longURLs))
+	for i, longURL := range longURLs {
+		hashes[i] = model.HashLongURL(longURL)
+	}
+
+	var urls []model.URL
+	result := r.db.Where("long_url_hash IN ? AND long_url IN ? AND owner_id = ? AND domain = ? AND expires_at IS NULL AND disabled_at IS NULL AND password_hash = '' AND redirect_code = 0",
+		hashes, longURLs, ownerID, domain).
+		Order("id").
+		Find(&urls)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	r.getLatency.Record(ctx, start)
+
+	return urls, nil
+}
+
+// List returns a page of the URLs matching the filter, ordered by ID.
+func (r Repository) List(ctx context.Context, filter model.URLFilter) ([]model.URL, error) {
+	start := time.Now()
+	_, span := r.tracer.Start(ctx, "urlRepo.list")
+	defer span.End()
+	query := r.db.Where("owner_id = ?", filter.OwnerID)
+	if filter.Domain != "" {
+		// the host is the third part of scheme://host/path, long URLs being stored in canonical form
+		query = query.Where("split_part(long_url, '/', 3) ILIKE ?", "%"+escapeLike(filter.Domain)+"%")
+	}
+
+	order := "id DESC"
+	if filter.Ascending {
+		order = "id"
+	}
+
+	if filter.AfterID != 0 {
+		if filter.Ascending {
+			query = query.Where("id > ?", filter.AfterID)
+		} else {
+			query = query.Where("id < ?", filter.AfterID)
+		}
+	}
+
+	var urls []model.URL
+	result := query.Order(order).Limit(filter.Limit).Find(&urls)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	r.getLatency.Record(ctx, start)
+
+	return urls, nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike makes the LIKE wildcards of s match literally.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
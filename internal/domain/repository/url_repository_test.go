@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/driver/postgres"
@@ -38,7 +39,7 @@ func (suite *URLRepositoryTestSuite) SetupTest() {
 	require.NoError(err)
 	gormDB, err := gorm.Open(postgres.New(postgres.Config{
 		Conn: db,
-	}), &gorm.Config{})
+	}), &gorm.Config{TranslateError: true})
 	require.NoError(err)
 	suite.repo = NewRepository(logrus.New(), gormDB, infra.NOOPTelemetry)
 	suite.mock = mock
@@ -59,9 +60,9 @@ func (suite *URLRepositoryTestSuite) TestURLRepository_Create_Success() {
 
 	for i, tc := range testCases {
 		suite.mock.ExpectBegin()
-		insertQuery := `INSERT INTO "urls" ("long_url","short_code","created_at","updated_at") VALUES ($1,$2,$3,$4) RETURNING "id"`
+		insertQuery := `INSERT INTO "urls" ("long_url","long_url_hash","original_url","domain","short_code","owner_id","expires_at","disabled_at","disabled_reason","password_hash","redirect_code","created_at","updated_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14) RETURNING "id"`
 		suite.mock.ExpectQuery(regexp.QuoteMeta(insertQuery)).
-			WithArgs(tc.input.LongURL, tc.input.ShortCode, AnyTime{}, AnyTime{}).
+			WithArgs(tc.input.LongURL, tc.input.LongURLHash, tc.input.OriginalURL, tc.input.Domain, tc.input.ShortCode, tc.input.OwnerID, nil, nil, "", "", 0, AnyTime{}, AnyTime{}, nil).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(i + 1))
 		suite.mock.ExpectCommit()
 		err := suite.repo.Create(context.TODO(), &tc.input)
@@ -88,9 +89,9 @@ func (suite *URLRepositoryTestSuite) TestURLRepository_Create_FailedInsert_Failu
 
 	for _, tc := range testCases {
 		suite.mock.ExpectBegin()
-		insertQuery := `INSERT INTO "urls" ("long_url","short_code","created_at","updated_at") VALUES ($1,$2,$3,$4) RETURNING "id"`
+		insertQuery := `INSERT INTO "urls" ("long_url","long_url_hash","original_url","domain","short_code","owner_id","expires_at","disabled_at","disabled_reason","password_hash","redirect_code","created_at","updated_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14) RETURNING "id"`
 		suite.mock.ExpectQuery(regexp.QuoteMeta(insertQuery)).
-			WithArgs(tc.input.LongURL, tc.input.ShortCode, AnyTime{}, AnyTime{}).
+			WithArgs(tc.input.LongURL, tc.input.LongURLHash, tc.input.OriginalURL, tc.input.Domain, tc.input.ShortCode, tc.input.OwnerID, nil, nil, "", "", 0, AnyTime{}, AnyTime{}, nil).
 			WillReturnError(errors.New("some err"))
 		suite.mock.ExpectRollback()
 		err := suite.repo.Create(context.TODO(), &tc.input)
@@ -102,9 +103,42 @@ func (suite *URLRepositoryTestSuite) TestURLRepository_Create_FailedInsert_Failu
 	}
 }
 
+func (suite *URLRepositoryTestSuite) TestURLRepository_Create_DuplicateShortCode_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		input model.URL
+	}{
+		{
+			input: model.URL{
+				LongURL:   "https://google.com",
+				ShortCode: "spring-sale",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.mock.ExpectBegin()
+		insertQuery := `INSERT INTO "urls" ("long_url","long_url_hash","original_url","domain","short_code","owner_id","expires_at","disabled_at","disabled_reason","password_hash","redirect_code","created_at","updated_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14) RETURNING "id"`
+		suite.mock.ExpectQuery(regexp.QuoteMeta(insertQuery)).
+			WithArgs(tc.input.LongURL, tc.input.LongURLHash, tc.input.OriginalURL, tc.input.Domain, tc.input.ShortCode, tc.input.OwnerID, nil, nil, "", "", 0, AnyTime{}, AnyTime{}, nil).
+			WillReturnError(&pgconn.PgError{Code: "23505"})
+		suite.mock.ExpectRollback()
+		err := suite.repo.Create(context.TODO(), &tc.input)
+
+		var duplicateErr *DuplicateShortCodeError
+		require.ErrorAs(err, &duplicateErr)
+		require.Equal(tc.input.ShortCode, duplicateErr.ShortCode)
+		require.ErrorIs(err, gorm.ErrDuplicatedKey)
+		if err = suite.mock.ExpectationsWereMet(); err != nil {
+			suite.T().Errorf("there were unfulfilled expectations: %s", err)
+		}
+	}
+}
+
 func (suite *URLRepositoryTestSuite) TestURLRepository_FindByShortCode_Success() {
 	require := suite.Require()
 	testCases := []struct {
+		domain      string
 		input       string
 		expectedURL model.URL
 	}{
@@ -118,17 +152,30 @@ func (suite *URLRepositoryTestSuite) TestURLRepository_FindByShortCode_Success()
 				UpdatedAt: time.Now(),
 			},
 		},
+		{
+			domain: "go.example.com",
+			input:  "A5rFt",
+			expectedURL: model.URL{
+				ID:        2,
+				LongURL:   "https://github.com",
+				Domain:    "go.example.com",
+				ShortCode: "A5rFt",
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			},
+		},
 	}
 
 	for _, tc := range testCases {
-		query := `SELECT \* FROM "urls" (.+)`
-		rows := sqlmock.NewRows([]string{"id", "long_url", "short_code", "created_at", "updated_at"}).
-			AddRow(tc.expectedURL.ID, tc.expectedURL.LongURL, tc.expectedURL.ShortCode, tc.expectedURL.CreatedAt, tc.expectedURL.UpdatedAt)
-		suite.mock.ExpectQuery(query).WithArgs(tc.input, 1).WillReturnRows(rows)
-		actualUrl, err := suite.repo.FindByShortCode(context.TODO(), tc.input)
+		query := `SELECT * FROM "urls" WHERE (domain = $1 AND short_code = $2) AND "urls"."deleted_at" IS NULL ORDER BY "urls"."id" LIMIT $3`
+		rows := sqlmock.NewRows([]string{"id", "long_url", "domain", "short_code", "created_at", "updated_at"}).
+			AddRow(tc.expectedURL.ID, tc.expectedURL.LongURL, tc.expectedURL.Domain, tc.expectedURL.ShortCode, tc.expectedURL.CreatedAt, tc.expectedURL.UpdatedAt)
+		suite.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(tc.domain, tc.input, 1).WillReturnRows(rows)
+		actualUrl, err := suite.repo.FindByShortCode(context.TODO(), tc.domain, tc.input)
 
 		require.NoError(err)
 		require.Equal(actualUrl.ShortCode, tc.expectedURL.ShortCode)
+		require.Equal(actualUrl.Domain, tc.expectedURL.Domain)
 		if err = suite.mock.ExpectationsWereMet(); err != nil {
 			suite.T().Errorf("there were unfulfilled expectations: %s", err)
 		}
@@ -147,8 +194,8 @@ func (suite *URLRepositoryTestSuite) TestURLRepository_FindByShortCode_Failure()
 
 	for _, tc := range testCases {
 		query := `SELECT \* FROM "urls" (.+)`
-		suite.mock.ExpectQuery(query).WithArgs(tc.input, 1).WillReturnError(gorm.ErrRecordNotFound)
-		_, err := suite.repo.FindByShortCode(context.TODO(), tc.input)
+		suite.mock.ExpectQuery(query).WithArgs("", tc.input, 1).WillReturnError(gorm.ErrRecordNotFound)
+		_, err := suite.repo.FindByShortCode(context.TODO(), "", tc.input)
 
 		require.Error(err)
 		require.Equal(gorm.ErrRecordNotFound, err)
@@ -158,6 +205,351 @@ func (suite *URLRepositoryTestSuite) TestURLRepository_FindByShortCode_Failure()
 	}
 }
 
+func (suite *URLRepositoryTestSuite) TestURLRepository_FindByLongURL_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		input       string
+		expectedURL model.URL
+	}{
+		{
+			input: "https://google.com",
+			expectedURL: model.URL{
+				ID:          1,
+				LongURL:     "https://google.com",
+				LongURLHash: model.HashLongURL("https://google.com"),
+				ShortCode:   "A5rFt",
+				OwnerID:     "alice",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		query := `SELECT * FROM "urls" WHERE (long_url_hash = $1 AND long_url = $2 AND owner_id = $3 AND domain = $4 AND expires_at IS NULL AND disabled_at IS NULL AND password_hash = '' AND redirect_code = 0) AND "urls"."deleted_at" IS NULL ORDER BY id,"urls"."id" LIMIT $5`
+		rows := sqlmock.NewRows([]string{"id", "long_url", "long_url_hash", "short_code"}).
+			AddRow(tc.expectedURL.ID, tc.expectedURL.LongURL, tc.expectedURL.LongURLHash, tc.expectedURL.ShortCode)
+		suite.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(tc.expectedURL.LongURLHash, tc.input, tc.expectedURL.OwnerID, "", 1).WillReturnRows(rows)
+		actualURL, err := suite.repo.FindByLongURL(context.TODO(), tc.expectedURL.OwnerID, "", tc.input)
+
+		require.NoError(err)
+		require.Equal(tc.expectedURL.ShortCode, actualURL.ShortCode)
+		if err = suite.mock.ExpectationsWereMet(); err != nil {
+			suite.T().Errorf("there were unfulfilled expectations: %s", err)
+		}
+	}
+}
+
+func (suite *URLRepositoryTestSuite) TestURLRepository_FindByLongURL_Failure() {
+	require := suite.Require()
+	query := `SELECT \* FROM "urls" (.+)`
+	suite.mock.ExpectQuery(query).WillReturnError(gorm.ErrRecordNotFound)
+	_, err := suite.repo.FindByLongURL(context.TODO(), "alice", "", "https://google.com")
+
+	require.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (suite *URLRepositoryTestSuite) TestURLRepository_UpdateLongURL_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		input model.URL
+	}{
+		{
+			input: model.URL{
+				ID:          1,
+				LongURL:     "https://github.com/",
+				LongURLHash: model.HashLongURL("https://github.com/"),
+				OriginalURL: "https://GitHub.com",
+				ShortCode:   "A5rFt",
+				OwnerID:     "alice",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.mock.ExpectBegin()
+		updateQuery := `UPDATE "urls" SET "long_url"=$1,"long_url_hash"=$2,"original_url"=$3,"updated_at"=$4 WHERE "urls"."deleted_at" IS NULL AND "id" = $5`
+		suite.mock.ExpectExec(regexp.QuoteMeta(updateQuery)).
+			WithArgs(tc.input.LongURL, tc.input.LongURLHash, tc.input.OriginalURL, AnyTime{}, tc.input.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		suite.mock.ExpectCommit()
+		err := suite.repo.UpdateLongURL(context.TODO(), &tc.input)
+
+		require.NoError(err)
+		if err = suite.mock.ExpectationsWereMet(); err != nil {
+			suite.T().Errorf("there were unfulfilled expectations: %s", err)
+		}
+	}
+}
+
+func (suite *URLRepositoryTestSuite) TestURLRepository_UpdateDisabled_Success() {
+	require := suite.Require()
+	disabledAt := time.Now()
+	testCases := []struct {
+		input model.URL
+	}{
+		{
+			input: model.URL{ID: 1, LongURL: "https://github.com/", ShortCode: "A5rFt", DisabledAt: &disabledAt, DisabledReason: "phishing"},
+		},
+		{
+			input: model.URL{ID: 1, LongURL: "https://github.com/", ShortCode: "A5rFt"},
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.mock.ExpectBegin()
+		updateQuery := `UPDATE "urls" SET "disabled_at"=$1,"disabled_reason"=$2,"updated_at"=$3 WHERE "urls"."deleted_at" IS NULL AND "id" = $4`
+		suite.mock.ExpectExec(regexp.QuoteMeta(updateQuery)).
+			WithArgs(tc.input.DisabledAt, tc.input.DisabledReason, AnyTime{}, tc.input.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		suite.mock.ExpectCommit()
+		err := suite.repo.UpdateDisabled(context.TODO(), &tc.input)
+
+		require.NoError(err)
+		if err = suite.mock.ExpectationsWereMet(); err != nil {
+			suite.T().Errorf("there were unfulfilled expectations: %s", err)
+		}
+	}
+}
+
+func (suite *URLRepositoryTestSuite) TestURLRepository_Update_Failure() {
+	require := suite.Require()
+	url := &model.URL{ID: 1, LongURL: "https://github.com/", ShortCode: "A5rFt"}
+	for _, update := range []func(ctx context.Context, url *model.URL) error{suite.repo.UpdateLongURL, suite.repo.UpdateDisabled} {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(`UPDATE "urls" (.+)`).WillReturnResult(sqlmock.NewResult(0, 0))
+		suite.mock.ExpectCommit()
+		err := update(context.TODO(), url)
+
+		require.ErrorIs(err, gorm.ErrRecordNotFound)
+	}
+}
+
+func (suite *URLRepositoryTestSuite) TestURLRepository_Delete_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		input string
+	}{
+		{
+			input: "A5rFt",
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.mock.ExpectBegin()
+		deleteQuery := `UPDATE "urls" SET "deleted_at"=$1 WHERE (domain = $2 AND short_code = $3) AND "urls"."deleted_at" IS NULL`
+		suite.mock.ExpectExec(regexp.QuoteMeta(deleteQuery)).WithArgs(AnyTime{}, "go.example.com", tc.input).WillReturnResult(sqlmock.NewResult(0, 1))
+		suite.mock.ExpectCommit()
+		err := suite.repo.Delete(context.TODO(), "go.example.com", tc.input)
+
+		require.NoError(err)
+		if err = suite.mock.ExpectationsWereMet(); err != nil {
+			suite.T().Errorf("there were unfulfilled expectations: %s", err)
+		}
+	}
+}
+
+func (suite *URLRepositoryTestSuite) TestURLRepository_Delete_Failure() {
+	require := suite.Require()
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(`UPDATE "urls" SET "deleted_at"(.+)`).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectCommit()
+	err := suite.repo.Delete(context.TODO(), "", "A5rFt")
+
+	require.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (suite *URLRepositoryTestSuite) TestURLRepository_CreateBatch_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		input []*model.URL
+	}{
+		{
+			input: []*model.URL{
+				{LongURL: "https://google.com/", ShortCode: "abcd"},
+				{LongURL: "https://github.com/", ShortCode: "efgh"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.mock.ExpectBegin()
+		insertQuery := `INSERT INTO "urls" ("long_url","long_url_hash","original_url","domain","short_code","owner_id","expires_at","disabled_at","disabled_reason","password_hash","redirect_code","created_at","updated_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14),($15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28) RETURNING "id"`
+		suite.mock.ExpectQuery(regexp.QuoteMeta(insertQuery)).
+			WithArgs(
+				tc.input[0].LongURL, tc.input[0].LongURLHash, tc.input[0].OriginalURL, tc.input[0].Domain, tc.input[0].ShortCode, tc.input[0].OwnerID, nil, nil, "", "", 0, AnyTime{}, AnyTime{}, nil,
+				tc.input[1].LongURL, tc.input[1].LongURLHash, tc.input[1].OriginalURL, tc.input[1].Domain, tc.input[1].ShortCode, tc.input[1].OwnerID, nil, nil, "", "", 0, AnyTime{}, AnyTime{}, nil,
+			).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+		suite.mock.ExpectCommit()
+		err := suite.repo.CreateBatch(context.TODO(), tc.input)
+
+		require.NoError(err)
+		require.Equal(uint(1), tc.input[0].ID)
+		require.Equal(uint(2), tc.input[1].ID)
+		if err = suite.mock.ExpectationsWereMet(); err != nil {
+			suite.T().Errorf("there were unfulfilled expectations: %s", err)
+		}
+	}
+}
+
+func (suite *URLRepositoryTestSuite) TestURLRepository_CreateBatch_Failure() {
+	require := suite.Require()
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`INSERT INTO "urls" (.+)`).WillReturnError(&pgconn.PgError{Code: "23505"})
+	suite.mock.ExpectRollback()
+	err := suite.repo.CreateBatch(context.TODO(), []*model.URL{{LongURL: "https://google.com/", ShortCode: "abcd"}})
+
+	require.ErrorIs(err, gorm.ErrDuplicatedKey)
+}
+
+func (suite *URLRepositoryTestSuite) TestURLRepository_FindExistingShortCodes_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		input    []string
+		expected []string
+	}{
+		{
+			input:    []string{"abcd", "efgh", "ijkl"},
+			expected: []string{"efgh"},
+		},
+	}
+
+	for _, tc := range testCases {
+		query := `SELECT "short_code" FROM "urls" WHERE domain = $1 AND short_code IN ($2,$3,$4)`
+		rows := sqlmock.NewRows([]string{"short_code"})
+		for _, shortCode := range tc.expected {
+			rows.AddRow(shortCode)
+		}
+
+		suite.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("", tc.input[0], tc.input[1], tc.input[2]).WillReturnRows(rows)
+		actual, err := suite.repo.FindExistingShortCodes(context.TODO(), "", tc.input)
+
+		require.NoError(err)
+		require.Equal(tc.expected, actual)
+		if err = suite.mock.ExpectationsWereMet(); err != nil {
+			suite.T().Errorf("there were unfulfilled expectations: %s", err)
+		}
+	}
+}
+
+func (suite *URLRepositoryTestSuite) TestURLRepository_CountByCodeLength_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		input    int
+		expected int64
+	}{
+		{
+			input:    5,
+			expected: 42,
+		},
+	}
+
+	for _, tc := range testCases {
+		query := `SELECT count(*) FROM "urls" WHERE length(short_code) = $1`
+		rows := sqlmock.NewRows([]string{"count"}).AddRow(tc.expected)
+		suite.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(tc.input).WillReturnRows(rows)
+		actual, err := suite.repo.CountByCodeLength(context.TODO(), tc.input)
+
+		require.NoError(err)
+		require.Equal(tc.expected, actual)
+		if err = suite.mock.ExpectationsWereMet(); err != nil {
+			suite.T().Errorf("there were unfulfilled expectations: %s", err)
+		}
+	}
+}
+
+func (suite *URLRepositoryTestSuite) TestURLRepository_ListShortCodes_Success() {
+	require := suite.Require()
+	query := `SELECT "id","domain","short_code" FROM "urls" WHERE id > $1 AND "urls"."deleted_at" IS NULL ORDER BY id LIMIT $2`
+	rows := sqlmock.NewRows([]string{"id", "domain", "short_code"}).
+		AddRow(43, "", "abcd").
+		AddRow(44, "go.example.com", "abcd")
+	suite.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(42, 2).WillReturnRows(rows)
+	actual, err := suite.repo.ListShortCodes(context.TODO(), 42, 2)
+
+	require.NoError(err)
+	require.Equal([]model.URL{{ID: 43, ShortCode: "abcd"}, {ID: 44, Domain: "go.example.com", ShortCode: "abcd"}}, actual)
+	require.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *URLRepositoryTestSuite) TestURLRepository_FindByLongURLs_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		input    []string
+		expected []model.URL
+	}{
+		{
+			input:    []string{"https://google.com/", "https://github.com/"},
+			expected: []model.URL{{ID: 1, LongURL: "https://google.com/", ShortCode: "abcd"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		query := `SELECT * FROM "urls" WHERE (long_url_hash IN ($1,$2) AND long_url IN ($3,$4) AND owner_id = $5 AND domain = $6 AND expires_at IS NULL AND disabled_at IS NULL AND password_hash = '' AND redirect_code = 0) AND "urls"."deleted_at" IS NULL ORDER BY id`
+		rows := sqlmock.NewRows([]string{"id", "long_url", "short_code"})
+		for _, url := range tc.expected {
+			rows.AddRow(url.ID, url.LongURL, url.ShortCode)
+		}
+
+		suite.mock.ExpectQuery(regexp.QuoteMeta(query)).
+			WithArgs(model.HashLongURL(tc.input[0]), model.HashLongURL(tc.input[1]), tc.input[0], tc.input[1], "alice", "").
+			WillReturnRows(rows)
+		actual, err := suite.repo.FindByLongURLs(context.TODO(), "alice", "", tc.input)
+
+		require.NoError(err)
+		require.Equal(tc.expected, actual)
+		if err = suite.mock.ExpectationsWereMet(); err != nil {
+			suite.T().Errorf("there were unfulfilled expectations: %s", err)
+		}
+	}
+}
+
+func (suite *URLRepositoryTestSuite) TestURLRepository_List_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		filter       model.URLFilter
+		expectedSQL  string
+		expectedArgs []driver.Value
+	}{
+		{
+			filter:       model.URLFilter{OwnerID: "alice", Limit: 21},
+			expectedSQL:  `SELECT * FROM "urls" WHERE owner_id = $1 AND "urls"."deleted_at" IS NULL ORDER BY id DESC LIMIT $2`,
+			expectedArgs: []driver.Value{"alice", 21},
+		},
+		{
+			filter:       model.URLFilter{OwnerID: "alice", Domain: "git_hub", AfterID: 42, Limit: 11},
+			expectedSQL:  `SELECT * FROM "urls" WHERE owner_id = $1 AND split_part(long_url, '/', 3) ILIKE $2 AND id < $3 AND "urls"."deleted_at" IS NULL ORDER BY id DESC LIMIT $4`,
+			expectedArgs: []driver.Value{"alice", `%git\_hub%`, 42, 11},
+		},
+		{
+			filter:       model.URLFilter{OwnerID: "alice", AfterID: 42, Ascending: true, Limit: 11},
+			expectedSQL:  `SELECT * FROM "urls" WHERE owner_id = $1 AND id > $2 AND "urls"."deleted_at" IS NULL ORDER BY id LIMIT $3`,
+			expectedArgs: []driver.Value{"alice", 42, 11},
+		},
+	}
+
+	for _, tc := range testCases {
+		rows := sqlmock.NewRows([]string{"id", "long_url", "short_code", "owner_id"}).
+			AddRow(43, "https://github.com/", "abcd", "alice")
+		suite.mock.ExpectQuery(regexp.QuoteMeta(tc.expectedSQL)).WithArgs(tc.expectedArgs...).WillReturnRows(rows)
+		actual, err := suite.repo.List(context.TODO(), tc.filter)
+
+		require.NoError(err)
+		require.Len(actual, 1)
+		if err = suite.mock.ExpectationsWereMet(); err != nil {
+			suite.T().Errorf("there were unfulfilled expectations: %s", err)
+		}
+	}
+}
+
+func (suite *URLRepositoryTestSuite) TestURLRepository_List_Failure() {
+	require := suite.Require()
+	suite.mock.ExpectQuery(`SELECT \* FROM "urls" (.+)`).WillReturnError(errors.New("connection refused"))
+	actual, err := suite.repo.List(context.TODO(), model.URLFilter{OwnerID: "alice", Limit: 21})
+
+	require.Error(err)
+	require.Nil(actual)
+}
+
 func TestURLRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(URLRepositoryTestSuite))
 }
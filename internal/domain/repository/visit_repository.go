@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+type VisitRepository struct {
+	logger        *logrus.Logger
+	db            *gorm.DB
+	tracer        trace.Tracer
+	createLatency infra.Latency
+}
+
+func NewVisitRepository(logger *logrus.Logger, db *gorm.DB, telemetry *infra.TelemetryProvider) *VisitRepository {
+	tracer := telemetry.TraceProvider.Tracer("visitRepo")
+	meter := telemetry.MeterProvider.Meter("visitRepo")
+	createLatency := infra.NewLatency(meter, "db.visit.create")
+
+	return &VisitRepository{
+		logger:        logger,
+		db:            db,
+		tracer:        tracer,
+		createLatency: createLatency,
+	}
+}
+
+func (r VisitRepository) Create(ctx context.Context, visit *model.Visit) error {
+	start := time.Now()
+	_, span := r.tracer.Start(ctx, "visitRepo.create")
+	defer span.End()
+	result := r.db.Create(visit)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	r.createLatency.Record(ctx, start)
+
+	return nil
+}
+
+// CountByShortCode returns the total number of visits and the number of distinct visitors of a short code on the domain.
+func (r VisitRepository) CountByShortCode(ctx context.Context, domain string, shortCode string) (total int64, unique int64, err error) {
+	_, span := r.tracer.Start(ctx, "visitRepo.count")
+	defer span.End()
+	var counts struct {
+		Total          int64
+		UniqueVisitors int64
+	}
+
+	result := r.db.Model(&model.Visit{}).
+		Select("COUNT(*) AS total, COUNT(DISTINCT ip_hash) AS unique_visitors").
+		Where("domain = ? AND short_code = ?", domain, shortCode).
+		Scan(&counts)
+	if result.Error != nil {
+		return 0, 0, result.Error
+	}
+
+	return counts.Total, counts.UniqueVisitors, nil
+}
+
+// DailyCounts returns the number of visits per UTC day since the given time, ordered by day.
+// Days without visits are not included.
+func (r VisitRepository) DailyCounts(ctx context.Context, domain string, shortCode string, since time.Time) ([]model.DailyStats, error) {
+	_, span := r.tracer.Start(ctx, "visitRepo.daily")
+	defer span.End()
+	var daily []model.DailyStats
+	result := r.db.Model(&model.Visit{}).
+		Select("TO_CHAR(DATE_TRUNC('day', visited_at AT TIME ZONE 'UTC'), 'YYYY-MM-DD') AS day, COUNT(*) AS clicks").
+		Where("domain = ? AND short_code = ? AND visited_at >= ?", domain, shortCode, since).
+		Group("day").
+		Order("day").
+		Scan(&daily)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return daily, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+type VisitRepositoryTestSuite struct {
+	suite.Suite
+	repo *VisitRepository
+	mock sqlmock.Sqlmock
+}
+
+func (suite *VisitRepositoryTestSuite) SetupTest() {
+	require := suite.Require()
+	db, mock, err := sqlmock.New()
+	require.NoError(err)
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{TranslateError: true})
+	require.NoError(err)
+	suite.repo = NewVisitRepository(logrus.New(), gormDB, infra.NOOPTelemetry)
+	suite.mock = mock
+}
+
+func (suite *VisitRepositoryTestSuite) TestVisitRepository_Create_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		input model.Visit
+	}{
+		{
+			input: model.Visit{
+				ShortCode: "A5rFt",
+				VisitedAt: time.Now(),
+				Referrer:  "https://news.ycombinator.com",
+				UserAgent: "curl/8.5.0",
+				IPHash:    "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+				Country:   "ZZ",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		suite.mock.ExpectBegin()
+		insertQuery := `INSERT INTO "visits" ("domain","short_code","visited_at","referrer","user_agent","ip_hash","country") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`
+		suite.mock.ExpectQuery(regexp.QuoteMeta(insertQuery)).
+			WithArgs(tc.input.Domain, tc.input.ShortCode, AnyTime{}, tc.input.Referrer, tc.input.UserAgent, tc.input.IPHash, tc.input.Country).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(i + 1))
+		suite.mock.ExpectCommit()
+		err := suite.repo.Create(context.TODO(), &tc.input)
+
+		require.NoError(err)
+		if err = suite.mock.ExpectationsWereMet(); err != nil {
+			suite.T().Errorf("there were unfulfilled expectations: %s", err)
+		}
+	}
+}
+
+func (suite *VisitRepositoryTestSuite) TestVisitRepository_CountByShortCode_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		input          string
+		expectedTotal  int64
+		expectedUnique int64
+	}{
+		{
+			input:          "A5rFt",
+			expectedTotal:  10,
+			expectedUnique: 4,
+		},
+	}
+
+	for _, tc := range testCases {
+		query := `SELECT COUNT(*) AS total, COUNT(DISTINCT ip_hash) AS unique_visitors FROM "visits" WHERE domain = $1 AND short_code = $2`
+		rows := sqlmock.NewRows([]string{"total", "unique_visitors"}).AddRow(tc.expectedTotal, tc.expectedUnique)
+		suite.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("", tc.input).WillReturnRows(rows)
+		total, unique, err := suite.repo.CountByShortCode(context.TODO(), "", tc.input)
+
+		require.NoError(err)
+		require.Equal(tc.expectedTotal, total)
+		require.Equal(tc.expectedUnique, unique)
+		if err = suite.mock.ExpectationsWereMet(); err != nil {
+			suite.T().Errorf("there were unfulfilled expectations: %s", err)
+		}
+	}
+}
+
+func (suite *VisitRepositoryTestSuite) TestVisitRepository_DailyCounts_Success() {
+	require := suite.Require()
+	since := time.Date(2024, time.May, 10, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		input    string
+		expected []model.DailyStats
+	}{
+		{
+			input: "A5rFt",
+			expected: []model.DailyStats{
+				{Day: "2024-05-10", Clicks: 3},
+				{Day: "2024-05-12", Clicks: 1},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		query := `SELECT TO_CHAR(DATE_TRUNC('day', visited_at AT TIME ZONE 'UTC'), 'YYYY-MM-DD') AS day, COUNT(*) AS clicks FROM "visits" WHERE domain = $1 AND short_code = $2 AND visited_at >= $3 GROUP BY "day" ORDER BY day`
+		rows := sqlmock.NewRows([]string{"day", "clicks"})
+		for _, d := range tc.expected {
+			rows.AddRow(d.Day, d.Clicks)
+		}
+
+		suite.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("", tc.input, since).WillReturnRows(rows)
+		actual, err := suite.repo.DailyCounts(context.TODO(), "", tc.input, since)
+
+		require.NoError(err)
+		require.Equal(tc.expected, actual)
+		if err = suite.mock.ExpectationsWereMet(); err != nil {
+			suite.T().Errorf("there were unfulfilled expectations: %s", err)
+		}
+	}
+}
+
+func (suite *VisitRepositoryTestSuite) TestVisitRepository_DailyCounts_Failure() {
+	require := suite.Require()
+	suite.mock.ExpectQuery(`SELECT (.+) FROM "visits" (.+)`).WillReturnError(errors.New("some err"))
+	_, err := suite.repo.DailyCounts(context.TODO(), "", "A5rFt", time.Now())
+
+	require.Error(err)
+}
+
+func TestVisitRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(VisitRepositoryTestSuite))
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+)
+
+const (
+	apiKeyPrefix = "shfy_"
+	// apiKeyBytes of randomness give 256 bits of entropy
+	apiKeyBytes        = 32
+	apiKeyVisibleChars = 12
+	maxOwnerLength     = 100
+)
+
+var (
+	ErrInvalidAPIKey  = errors.New("invalid api key")
+	ErrInvalidOwner   = errors.New("invalid owner")
+	ErrAPIKeyNotFound = errors.New("api key not found")
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *model.APIKey) error
+	FindActiveByHash(ctx context.Context, keyHash string) (*model.APIKey, error)
+	List(ctx context.Context) ([]model.APIKey, error)
+	Revoke(ctx context.Context, id uint) error
+}
+
+type APIKeyService struct {
+	logger *logrus.Logger
+	repo   APIKeyRepository
+}
+
+func NewAPIKeyService(logger *logrus.Logger, repo APIKeyRepository) *APIKeyService {
+	return &APIKeyService{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// Create issues a new key for the owner, an admin one if asked. The returned plaintext key cannot be recovered later.
+func (svc *APIKeyService) Create(ctx context.Context, owner string, admin bool) (string, *model.APIKey, error) {
+	owner = strings.TrimSpace(owner)
+	if owner == "" || len(owner) > maxOwnerLength {
+		return "", nil, fmt.Errorf("%w: must be between 1 and %d characters", ErrInvalidOwner, maxOwnerLength)
+	}
+
+	secret := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, err
+	}
+
+	rawKey := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	key := &model.APIKey{
+		Owner:   owner,
+		Prefix:  rawKey[:apiKeyVisibleChars],
+		KeyHash: hashAPIKey(rawKey),
+		Admin:   admin,
+	}
+	if err := svc.repo.Create(ctx, key); err != nil {
+		return "", nil, err
+	}
+
+	svc.logger.WithFields(logrus.Fields{
+		"owner":  owner,
+		"prefix": key.Prefix,
+		"admin":  admin,
+	}).Info("Create API key")
+
+	return rawKey, key, nil
+}
+
+func (svc *APIKeyService) List(ctx context.Context) ([]model.APIKey, error) {
+	return svc.repo.List(ctx)
+}
+
+func (svc *APIKeyService) Revoke(ctx context.Context, id uint) error {
+	if err := svc.repo.Revoke(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAPIKeyNotFound
+		}
+
+		return err
+	}
+
+	svc.logger.WithField("id", id).Info("Revoke API key")
+
+	return nil
+}
+
+// Authenticate returns the active key matching the plaintext key.
+func (svc *APIKeyService) Authenticate(ctx context.Context, rawKey string) (*model.APIKey, error) {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := svc.repo.FindActiveByHash(ctx, hashAPIKey(rawKey))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func hashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+type (
+	ownerKey struct{}
+	adminKey struct{}
+)
+
+// WithOwner returns a copy of ctx carrying the owner of the authenticated API key.
+func WithOwner(ctx context.Context, owner string) context.Context {
+	return context.WithValue(ctx, ownerKey{}, owner)
+}
+
+// OwnerFromContext returns the owner attached by WithOwner, if any.
+func OwnerFromContext(ctx context.Context) (string, bool) {
+	owner, ok := ctx.Value(ownerKey{}).(string)
+	return owner, ok
+}
+
+// WithAdmin returns a copy of ctx marking the request as made with an admin API key.
+func WithAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminKey{}, true)
+}
+
+// IsAdmin reports whether the request was made with an admin API key.
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey{}).(bool)
+	return admin
+}
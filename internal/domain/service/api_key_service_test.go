@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	genMock "github.com/miladbarzideh/shortify/internal/domain/service/mock"
+)
+
+type APIKeyServiceTestSuite struct {
+	suite.Suite
+	service  *APIKeyService
+	mockRepo *genMock.APIKeyRepository
+}
+
+func (suite *APIKeyServiceTestSuite) SetupTest() {
+	suite.mockRepo = new(genMock.APIKeyRepository)
+	suite.service = NewAPIKeyService(logrus.New(), suite.mockRepo)
+}
+
+func (suite *APIKeyServiceTestSuite) TestAPIKeyService_Create_Success() {
+	require := suite.Require()
+	var stored *model.APIKey
+	suite.mockRepo.On("Create", context.TODO(), testifyMock.Anything).
+		Run(func(args testifyMock.Arguments) { stored = args.Get(1).(*model.APIKey) }).
+		Return(nil).Once()
+	rawKey, key, err := suite.service.Create(context.TODO(), " alice ", true)
+
+	require.NoError(err)
+	require.True(strings.HasPrefix(rawKey, apiKeyPrefix))
+	require.Equal("alice", key.Owner)
+	require.True(key.Admin)
+	require.Equal(stored, key)
+	require.Equal(hashAPIKey(rawKey), key.KeyHash)
+	require.True(strings.HasPrefix(rawKey, key.Prefix))
+}
+
+func (suite *APIKeyServiceTestSuite) TestAPIKeyService_Create_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		owner string
+	}{
+		{owner: "  "},
+		{owner: strings.Repeat("a", maxOwnerLength+1)},
+	}
+
+	for _, tc := range testCases {
+		rawKey, key, err := suite.service.Create(context.TODO(), tc.owner, false)
+
+		require.ErrorIs(err, ErrInvalidOwner)
+		require.Empty(rawKey)
+		require.Nil(key)
+	}
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", testifyMock.Anything, testifyMock.Anything)
+}
+
+func (suite *APIKeyServiceTestSuite) TestAPIKeyService_Authenticate_Success() {
+	require := suite.Require()
+	rawKey := apiKeyPrefix + "c2VjcmV0"
+	suite.mockRepo.On("FindActiveByHash", context.TODO(), hashAPIKey(rawKey)).
+		Return(&model.APIKey{ID: 1, Owner: "alice"}, nil).Once()
+	key, err := suite.service.Authenticate(context.TODO(), rawKey)
+
+	require.NoError(err)
+	require.Equal("alice", key.Owner)
+}
+
+func (suite *APIKeyServiceTestSuite) TestAPIKeyService_Authenticate_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		input   string
+		findErr error
+	}{
+		{
+			input: "c2VjcmV0",
+		},
+		{
+			input:   apiKeyPrefix + "revoked",
+			findErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.findErr != nil {
+			suite.mockRepo.On("FindActiveByHash", context.TODO(), hashAPIKey(tc.input)).Return(nil, tc.findErr).Once()
+		}
+
+		key, err := suite.service.Authenticate(context.TODO(), tc.input)
+
+		require.ErrorIs(err, ErrInvalidAPIKey)
+		require.Nil(key)
+	}
+}
+
+func (suite *APIKeyServiceTestSuite) TestAPIKeyService_Revoke() {
+	require := suite.Require()
+	suite.mockRepo.On("Revoke", context.TODO(), uint(1)).Return(nil).Once()
+	suite.mockRepo.On("Revoke", context.TODO(), uint(2)).Return(gorm.ErrRecordNotFound).Once()
+
+	require.NoError(suite.service.Revoke(context.TODO(), 1))
+	require.ErrorIs(suite.service.Revoke(context.TODO(), 2), ErrAPIKeyNotFound)
+}
+
+func (suite *APIKeyServiceTestSuite) TestAPIKeyService_OwnerContext() {
+	require := suite.Require()
+	_, ok := OwnerFromContext(context.TODO())
+	require.False(ok)
+
+	owner, ok := OwnerFromContext(WithOwner(context.TODO(), "alice"))
+	require.True(ok)
+	require.Equal("alice", owner)
+}
+
+func TestAPIKeyServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyServiceTestSuite))
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/pkg/urlpolicy"
+)
+
+var (
+	ErrInvalidHost    = errors.New("invalid host")
+	ErrHostBlocked    = errors.New("host already blocked")
+	ErrHostNotBlocked = errors.New("host not blocked")
+)
+
+type BlockedHostRepository interface {
+	Create(ctx context.Context, host *model.BlockedHost) error
+	List(ctx context.Context) ([]model.BlockedHost, error)
+	Delete(ctx context.Context, host string) error
+}
+
+// BlocklistService manages the destination hosts blocked at runtime, e.g. reported phishing sites.
+// Lookups are served from memory, refreshed every minute.
+type BlocklistService struct {
+	logger *logrus.Logger
+	repo   BlockedHostRepository
+	hosts  *hostCache
+}
+
+func NewBlocklistService(logger *logrus.Logger, repo BlockedHostRepository) *BlocklistService {
+	return &BlocklistService{
+		logger: logger,
+		repo:   repo,
+		hosts: newHostCache(func(ctx context.Context) ([]string, error) {
+			blocked, err := repo.List(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			hosts := make([]string, len(blocked))
+			for i, host := range blocked {
+				hosts[i] = host.Host
+			}
+
+			return hosts, nil
+		}),
+	}
+}
+
+// Block rejects new links to the host and its subdomains. Existing links keep redirecting
+// unless destinations are also checked on redirect.
+func (svc *BlocklistService) Block(ctx context.Context, host string, reason string) (*model.BlockedHost, error) {
+	host = normalizeBlockedHost(host)
+	if _, err := netip.ParseAddr(host); err != nil && !hostRegex.MatchString(host) {
+		return nil, fmt.Errorf("%w: '%s'", ErrInvalidHost, host)
+	}
+
+	blocked := &model.BlockedHost{Host: host, Reason: strings.TrimSpace(reason)}
+	if err := svc.repo.Create(ctx, blocked); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, fmt.Errorf("%w: '%s'", ErrHostBlocked, host)
+		}
+
+		return nil, err
+	}
+
+	svc.hosts.invalidate()
+
+	return blocked, nil
+}
+
+func (svc *BlocklistService) List(ctx context.Context) ([]model.BlockedHost, error) {
+	return svc.repo.List(ctx)
+}
+
+func (svc *BlocklistService) Unblock(ctx context.Context, host string) error {
+	if err := svc.repo.Delete(ctx, normalizeBlockedHost(host)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrHostNotBlocked
+		}
+
+		return err
+	}
+
+	svc.hosts.invalidate()
+
+	return nil
+}
+
+// MatchBlocked returns the blocked entry that is the host or one of its parent domains.
+func (svc *BlocklistService) MatchBlocked(ctx context.Context, host string) (string, bool, error) {
+	hosts, err := svc.hosts.get(ctx)
+	if err != nil {
+		return "", false, err
+	}
+
+	matched, ok := urlpolicy.MatchHost(hosts, host)
+
+	return matched, ok, nil
+}
+
+func normalizeBlockedHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	genMock "github.com/miladbarzideh/shortify/internal/domain/service/mock"
+)
+
+type BlocklistServiceTestSuite struct {
+	suite.Suite
+	service  *BlocklistService
+	mockRepo *genMock.BlockedHostRepository
+}
+
+func (suite *BlocklistServiceTestSuite) SetupTest() {
+	suite.mockRepo = new(genMock.BlockedHostRepository)
+	suite.service = NewBlocklistService(logrus.New(), suite.mockRepo)
+}
+
+func (suite *BlocklistServiceTestSuite) TestBlocklistService_Block_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		host     string
+		expected string
+	}{
+		{host: " Evil.COM. ", expected: "evil.com"},
+		{host: "203.0.113.7", expected: "203.0.113.7"},
+	}
+
+	for _, tc := range testCases {
+		suite.mockRepo.On("Create", context.TODO(), &model.BlockedHost{Host: tc.expected, Reason: "phishing"}).Return(nil).Once()
+		blocked, err := suite.service.Block(context.TODO(), tc.host, " phishing ")
+
+		require.NoError(err)
+		require.Equal(tc.expected, blocked.Host)
+	}
+}
+
+func (suite *BlocklistServiceTestSuite) TestBlocklistService_Block_Failure() {
+	require := suite.Require()
+	_, err := suite.service.Block(context.TODO(), "https://evil.com/", "")
+	require.ErrorIs(err, ErrInvalidHost)
+
+	suite.mockRepo.On("Create", context.TODO(), testifyMock.Anything).Return(gorm.ErrDuplicatedKey).Once()
+	_, err = suite.service.Block(context.TODO(), "evil.com", "")
+	require.ErrorIs(err, ErrHostBlocked)
+}
+
+func (suite *BlocklistServiceTestSuite) TestBlocklistService_Unblock_Failure() {
+	require := suite.Require()
+	suite.mockRepo.On("Delete", context.TODO(), "evil.com").Return(gorm.ErrRecordNotFound).Once()
+	err := suite.service.Unblock(context.TODO(), "Evil.com")
+
+	require.ErrorIs(err, ErrHostNotBlocked)
+}
+
+func (suite *BlocklistServiceTestSuite) TestBlocklistService_MatchBlocked() {
+	require := suite.Require()
+	suite.mockRepo.On("List", context.TODO()).Return([]model.BlockedHost{{Host: "evil.com"}}, nil).Once()
+	testCases := []struct {
+		host     string
+		expected string
+	}{
+		{host: "evil.com", expected: "evil.com"},
+		{host: "login.evil.com", expected: "evil.com"},
+		{host: "notevil.com"},
+	}
+
+	for _, tc := range testCases {
+		matched, blocked, err := suite.service.MatchBlocked(context.TODO(), tc.host)
+
+		require.NoError(err)
+		require.Equal(tc.expected != "", blocked, tc.host)
+		require.Equal(tc.expected, matched, tc.host)
+	}
+
+	// a blocked host is picked up without waiting for the cache to expire
+	suite.mockRepo.On("Create", context.TODO(), testifyMock.Anything).Return(nil).Once()
+	suite.mockRepo.On("List", context.TODO()).Return([]model.BlockedHost{{Host: "evil.com"}, {Host: "notevil.com"}}, nil).Once()
+	_, err := suite.service.Block(context.TODO(), "notevil.com", "")
+	require.NoError(err)
+	_, blocked, err := suite.service.MatchBlocked(context.TODO(), "notevil.com")
+	require.NoError(err)
+	require.True(blocked)
+}
+
+func TestBlocklistServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(BlocklistServiceTestSuite))
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+)
+
+// hostCacheTTL bounds how long a domain or blocked host added or removed with another instance goes unnoticed.
+const hostCacheTTL = time.Minute
+
+var (
+	ErrInvalidDomain  = errors.New("invalid domain")
+	ErrUnknownDomain  = errors.New("unknown domain")
+	ErrDomainExists   = errors.New("domain already registered")
+	ErrDomainNotFound = errors.New("domain not found")
+
+	hostRegex = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]([a-z0-9-]{0,61}[a-z0-9])?$`)
+)
+
+type DomainRepository interface {
+	Create(ctx context.Context, domain *model.Domain) error
+	List(ctx context.Context) ([]model.Domain, error)
+	Delete(ctx context.Context, host string) error
+}
+
+// DomainService manages the branded short domains. Lookups are served from memory, refreshed every minute.
+type DomainService struct {
+	logger *logrus.Logger
+	repo   DomainRepository
+	hosts  *hostCache
+}
+
+func NewDomainService(logger *logrus.Logger, repo DomainRepository) *DomainService {
+	return &DomainService{
+		logger: logger,
+		repo:   repo,
+		hosts: newHostCache(func(ctx context.Context) ([]string, error) {
+			domains, err := repo.List(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			hosts := make([]string, len(domains))
+			for i, domain := range domains {
+				hosts[i] = domain.Host
+			}
+
+			return hosts, nil
+		}),
+	}
+}
+
+func (svc *DomainService) Add(ctx context.Context, host string) (*model.Domain, error) {
+	host = NormalizeHost(host)
+	if !hostRegex.MatchString(host) || len(host) > 253 {
+		return nil, fmt.Errorf("%w: '%s'", ErrInvalidDomain, host)
+	}
+
+	domain := &model.Domain{Host: host}
+	if err := svc.repo.Create(ctx, domain); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, fmt.Errorf("%w: '%s'", ErrDomainExists, host)
+		}
+
+		return nil, err
+	}
+
+	svc.hosts.invalidate()
+
+	return domain, nil
+}
+
+func (svc *DomainService) List(ctx context.Context) ([]model.Domain, error) {
+	return svc.repo.List(ctx)
+}
+
+func (svc *DomainService) Remove(ctx context.Context, host string) error {
+	if err := svc.repo.Delete(ctx, NormalizeHost(host)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrDomainNotFound
+		}
+
+		return err
+	}
+
+	svc.hosts.invalidate()
+
+	return nil
+}
+
+// IsRegistered reports whether the host is a registered short domain.
+func (svc *DomainService) IsRegistered(ctx context.Context, host string) (bool, error) {
+	hosts, err := svc.hosts.get(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := hosts[NormalizeHost(host)]
+
+	return ok, nil
+}
+
+// hostCache keeps a set of hosts read from the database in memory for hostCacheTTL.
+type hostCache struct {
+	load func(ctx context.Context) ([]string, error)
+
+	mu       sync.RWMutex
+	hosts    map[string]struct{}
+	loadedAt time.Time
+	now      func() time.Time
+}
+
+func newHostCache(load func(ctx context.Context) ([]string, error)) *hostCache {
+	return &hostCache{
+		load: load,
+		now:  time.Now,
+	}
+}
+
+func (c *hostCache) get(ctx context.Context) (map[string]struct{}, error) {
+	c.mu.RLock()
+	hosts, fresh := c.hosts, c.now().Sub(c.loadedAt) < hostCacheTTL
+	c.mu.RUnlock()
+	if hosts != nil && fresh {
+		return hosts, nil
+	}
+
+	list, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts = make(map[string]struct{}, len(list))
+	for _, host := range list {
+		hosts[host] = struct{}{}
+	}
+
+	c.mu.Lock()
+	c.hosts, c.loadedAt = hosts, c.now()
+	c.mu.Unlock()
+
+	return hosts, nil
+}
+
+func (c *hostCache) invalidate() {
+	c.mu.Lock()
+	c.hosts = nil
+	c.mu.Unlock()
+}
+
+// NormalizeHost lowercases the host and strips its port and trailing dot, as found in a Host header.
+func NormalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if i := strings.LastIndexByte(host, ':'); i != -1 && !strings.Contains(host[i:], "]") {
+		host = host[:i]
+	}
+
+	return strings.TrimSuffix(host, ".")
+}
+
+type domainKey struct{}
+
+// WithDomain returns a copy of ctx carrying the short domain the request is about, empty for the default one.
+func WithDomain(ctx context.Context, domain string) context.Context {
+	return context.WithValue(ctx, domainKey{}, domain)
+}
+
+// DomainFromContext returns the domain attached by WithDomain, or the default domain.
+func DomainFromContext(ctx context.Context) string {
+	domain, _ := ctx.Value(domainKey{}).(string)
+	return domain
+}
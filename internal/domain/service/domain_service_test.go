@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	genMock "github.com/miladbarzideh/shortify/internal/domain/service/mock"
+)
+
+type DomainServiceTestSuite struct {
+	suite.Suite
+	service  *DomainService
+	mockRepo *genMock.DomainRepository
+}
+
+func (suite *DomainServiceTestSuite) SetupTest() {
+	suite.mockRepo = new(genMock.DomainRepository)
+	suite.service = NewDomainService(logrus.New(), suite.mockRepo)
+}
+
+func (suite *DomainServiceTestSuite) TestDomainService_Add_Success() {
+	require := suite.Require()
+	suite.mockRepo.On("Create", context.TODO(), &model.Domain{Host: "go.example.com"}).Return(nil).Once()
+	domain, err := suite.service.Add(context.TODO(), " Go.Example.com. ")
+
+	require.NoError(err)
+	require.Equal("go.example.com", domain.Host)
+}
+
+func (suite *DomainServiceTestSuite) TestDomainService_Add_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		host    string
+		repoErr error
+		err     error
+	}{
+		{host: "localhost", err: ErrInvalidDomain},
+		{host: "go_example.com", err: ErrInvalidDomain},
+		{host: "https://go.example.com/", err: ErrInvalidDomain},
+		{host: "go.example.com", repoErr: gorm.ErrDuplicatedKey, err: ErrDomainExists},
+	}
+
+	for _, tc := range testCases {
+		if tc.repoErr != nil {
+			suite.mockRepo.On("Create", context.TODO(), testifyMock.Anything).Return(tc.repoErr).Once()
+		}
+
+		domain, err := suite.service.Add(context.TODO(), tc.host)
+
+		require.ErrorIs(err, tc.err, tc.host)
+		require.Nil(domain)
+	}
+}
+
+func (suite *DomainServiceTestSuite) TestDomainService_Remove_Failure() {
+	require := suite.Require()
+	suite.mockRepo.On("Delete", context.TODO(), "go.example.com").Return(gorm.ErrRecordNotFound).Once()
+	err := suite.service.Remove(context.TODO(), "go.example.com")
+
+	require.ErrorIs(err, ErrDomainNotFound)
+}
+
+func (suite *DomainServiceTestSuite) TestDomainService_IsRegistered_Success() {
+	require := suite.Require()
+	now := time.Now()
+	suite.service.hosts.now = func() time.Time { return now }
+	suite.mockRepo.On("List", context.TODO()).Return([]model.Domain{{Host: "go.example.com"}}, nil).Once()
+
+	for _, host := range []string{"go.example.com", "GO.example.com:443", "example.com"} {
+		registered, err := suite.service.IsRegistered(context.TODO(), host)
+
+		require.NoError(err)
+		require.Equal(host != "example.com", registered, host)
+	}
+
+	// the registered domains are read once per minute
+	suite.mockRepo.AssertNumberOfCalls(suite.T(), "List", 1)
+	now = now.Add(hostCacheTTL)
+	suite.mockRepo.On("List", context.TODO()).Return([]model.Domain{}, nil).Once()
+	registered, err := suite.service.IsRegistered(context.TODO(), "go.example.com")
+
+	require.NoError(err)
+	require.False(registered)
+}
+
+func (suite *DomainServiceTestSuite) TestDomainService_IsRegistered_Failure() {
+	require := suite.Require()
+	suite.mockRepo.On("List", context.TODO()).Return(nil, errors.New("connection refused")).Once()
+	_, err := suite.service.IsRegistered(context.TODO(), "go.example.com")
+
+	require.Error(err)
+}
+
+func TestDomainServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(DomainServiceTestSuite))
+}
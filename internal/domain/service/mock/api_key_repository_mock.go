@@ -0,0 +1,41 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+)
+
+type APIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *APIKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *APIKeyRepository) FindActiveByHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
+	args := m.Called(ctx, keyHash)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.APIKey), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *APIKeyRepository) List(ctx context.Context) ([]model.APIKey, error) {
+	args := m.Called(ctx)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.APIKey), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *APIKeyRepository) Revoke(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
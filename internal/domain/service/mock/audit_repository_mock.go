@@ -0,0 +1,18 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+)
+
+type AuditRepository struct {
+	mock.Mock
+}
+
+func (m *AuditRepository) Create(ctx context.Context, entry *model.AuditLog) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
@@ -1,6 +1,8 @@
 package mock
 
 import (
+	"context"
+
 	"github.com/stretchr/testify/mock"
 )
 
@@ -8,7 +10,16 @@ type Generator struct {
 	mock.Mock
 }
 
-func (g *Generator) GenerateShortURLCode() string {
+func (g *Generator) GenerateShortURLCode(ctx context.Context) (string, error) {
+	args := g.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (g *Generator) Length() int {
 	args := g.Called()
-	return args.String(0)
+	return args.Int(0)
+}
+
+func (g *Generator) SetLength(length int) {
+	g.Called(length)
 }
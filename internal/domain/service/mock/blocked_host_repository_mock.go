@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+)
+
+type BlockedHostRepository struct {
+	mock.Mock
+}
+
+func (m *BlockedHostRepository) Create(ctx context.Context, host *model.BlockedHost) error {
+	args := m.Called(ctx, host)
+	return args.Error(0)
+}
+
+func (m *BlockedHostRepository) List(ctx context.Context) ([]model.BlockedHost, error) {
+	args := m.Called(ctx)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.BlockedHost), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *BlockedHostRepository) Delete(ctx context.Context, host string) error {
+	args := m.Called(ctx, host)
+	return args.Error(0)
+}
@@ -17,11 +17,31 @@ func (m *CacheRepository) Set(ctx context.Context, url *model.URL) error {
 	return args.Error(0)
 }
 
-func (m *CacheRepository) Get(ctx context.Context, shortCode string) (*model.URL, error) {
-	args := m.Called(ctx, shortCode)
+func (m *CacheRepository) Replace(ctx context.Context, url *model.URL) error {
+	args := m.Called(ctx, url)
+	return args.Error(0)
+}
+
+func (m *CacheRepository) Get(ctx context.Context, domain string, shortCode string) (*model.URL, error) {
+	args := m.Called(ctx, domain, shortCode)
 	if args.Get(0) != nil {
 		return args.Get(0).(*model.URL), args.Error(1)
 	}
 
 	return nil, args.Error(1)
 }
+
+func (m *CacheRepository) Delete(ctx context.Context, domain string, shortCode string) error {
+	args := m.Called(ctx, domain, shortCode)
+	return args.Error(0)
+}
+
+func (m *CacheRepository) SetMissing(ctx context.Context, domain string, shortCode string) error {
+	args := m.Called(ctx, domain, shortCode)
+	return args.Error(0)
+}
+
+func (m *CacheRepository) DeleteMissing(ctx context.Context, domain string, shortCodes []string) error {
+	args := m.Called(ctx, domain, shortCodes)
+	return args.Error(0)
+}
@@ -0,0 +1,33 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type CodeFilter struct {
+	mock.Mock
+}
+
+func (m *CodeFilter) Add(ctx context.Context, items ...string) error {
+	args := m.Called(ctx, items)
+	return args.Error(0)
+}
+
+func (m *CodeFilter) MightContain(ctx context.Context, item string) (bool, error) {
+	args := m.Called(ctx, item)
+	return args.Bool(0), args.Error(1)
+}
+
+// Rebuild passes the items loaded to Add, so tests can expect them there.
+func (m *CodeFilter) Rebuild(ctx context.Context, load func(add func(items ...string) error) error) error {
+	args := m.Called(ctx)
+	if err := load(func(items ...string) error {
+		return m.Add(ctx, items...)
+	}); err != nil {
+		return err
+	}
+
+	return args.Error(0)
+}
@@ -0,0 +1,16 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type DestinationPolicy struct {
+	mock.Mock
+}
+
+func (m *DestinationPolicy) Check(ctx context.Context, longURL string) error {
+	args := m.Called(ctx, longURL)
+	return args.Error(0)
+}
@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+)
+
+type DomainRepository struct {
+	mock.Mock
+}
+
+func (m *DomainRepository) Create(ctx context.Context, domain *model.Domain) error {
+	args := m.Called(ctx, domain)
+	return args.Error(0)
+}
+
+func (m *DomainRepository) List(ctx context.Context) ([]model.Domain, error) {
+	args := m.Called(ctx)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Domain), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *DomainRepository) Delete(ctx context.Context, host string) error {
+	args := m.Called(ctx, host)
+	return args.Error(0)
+}
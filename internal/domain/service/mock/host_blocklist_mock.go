@@ -0,0 +1,16 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type HostBlocklist struct {
+	mock.Mock
+}
+
+func (m *HostBlocklist) MatchBlocked(ctx context.Context, host string) (string, bool, error) {
+	args := m.Called(ctx, host)
+	return args.String(0), args.Bool(1), args.Error(2)
+}
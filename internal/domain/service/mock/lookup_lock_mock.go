@@ -0,0 +1,22 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type LookupLock struct {
+	mock.Mock
+}
+
+func (m *LookupLock) Acquire(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	args := m.Called(ctx, key, ttl)
+	return args.String(0), args.Bool(1), args.Error(2)
+}
+
+func (m *LookupLock) Release(ctx context.Context, key string, token string) error {
+	args := m.Called(ctx, key, token)
+	return args.Error(0)
+}
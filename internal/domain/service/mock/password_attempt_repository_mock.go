@@ -0,0 +1,27 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type PasswordAttemptRepository struct {
+	mock.Mock
+}
+
+func (m *PasswordAttemptRepository) RecordAttempt(ctx context.Context,
+	domain string,
+	shortCode string,
+	clientIP string,
+	window time.Duration,
+) (int64, error) {
+	args := m.Called(ctx, domain, shortCode, clientIP, window)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *PasswordAttemptRepository) Reset(ctx context.Context, domain string, shortCode string, clientIP string) error {
+	args := m.Called(ctx, domain, shortCode, clientIP)
+	return args.Error(0)
+}
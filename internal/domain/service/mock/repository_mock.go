@@ -17,11 +17,81 @@ func (m *Repository) Create(ctx context.Context, url *model.URL) error {
 	return args.Error(0)
 }
 
-func (m *Repository) FindByShortCode(ctx context.Context, shortCode string) (*model.URL, error) {
-	args := m.Called(ctx, shortCode)
+func (m *Repository) FindByShortCode(ctx context.Context, domain string, shortCode string) (*model.URL, error) {
+	args := m.Called(ctx, domain, shortCode)
 	if args.Get(0) != nil {
 		return args.Get(0).(*model.URL), args.Error(1)
 	}
 
 	return nil, args.Error(1)
 }
+
+func (m *Repository) FindByLongURL(ctx context.Context, ownerID string, domain string, longURL string) (*model.URL, error) {
+	args := m.Called(ctx, ownerID, domain, longURL)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.URL), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *Repository) CreateBatch(ctx context.Context, urls []*model.URL) error {
+	args := m.Called(ctx, urls)
+	return args.Error(0)
+}
+
+func (m *Repository) FindExistingShortCodes(ctx context.Context, domain string, shortCodes []string) ([]string, error) {
+	args := m.Called(ctx, domain, shortCodes)
+	if args.Get(0) != nil {
+		return args.Get(0).([]string), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *Repository) CountByCodeLength(ctx context.Context, length int) (int64, error) {
+	args := m.Called(ctx, length)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *Repository) FindByLongURLs(ctx context.Context, ownerID string, domain string, longURLs []string) ([]model.URL, error) {
+	args := m.Called(ctx, ownerID, domain, longURLs)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.URL), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *Repository) UpdateLongURL(ctx context.Context, url *model.URL) error {
+	args := m.Called(ctx, url)
+	return args.Error(0)
+}
+
+func (m *Repository) UpdateDisabled(ctx context.Context, url *model.URL) error {
+	args := m.Called(ctx, url)
+	return args.Error(0)
+}
+
+func (m *Repository) Delete(ctx context.Context, domain string, shortCode string) error {
+	args := m.Called(ctx, domain, shortCode)
+	return args.Error(0)
+}
+
+func (m *Repository) List(ctx context.Context, filter model.URLFilter) ([]model.URL, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.URL), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *Repository) ListShortCodes(ctx context.Context, afterID uint, limit int) ([]model.URL, error) {
+	args := m.Called(ctx, afterID, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.URL), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
@@ -0,0 +1,33 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+)
+
+type VisitRepository struct {
+	mock.Mock
+}
+
+func (m *VisitRepository) Create(ctx context.Context, visit *model.Visit) error {
+	args := m.Called(ctx, visit)
+	return args.Error(0)
+}
+
+func (m *VisitRepository) CountByShortCode(ctx context.Context, domain string, shortCode string) (int64, int64, error) {
+	args := m.Called(ctx, domain, shortCode)
+	return args.Get(0).(int64), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *VisitRepository) DailyCounts(ctx context.Context, domain string, shortCode string, since time.Time) ([]model.DailyStats, error) {
+	args := m.Called(ctx, domain, shortCode, since)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.DailyStats), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
@@ -0,0 +1,22 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// WorkerPool runs accepted jobs synchronously, so tests can assert on their side effects.
+type WorkerPool struct {
+	mock.Mock
+}
+
+func (m *WorkerPool) Submit(job func(ctx context.Context)) error {
+	args := m.Called()
+	if err := args.Error(0); err != nil {
+		return err
+	}
+
+	job(context.TODO())
+	return nil
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+)
+
+var (
+	ErrEmptyBatch    = errors.New("empty batch")
+	ErrBatchTooLarge = errors.New("batch too large")
+	ErrMixedDomains  = errors.New("all URLs of a batch must use its domain")
+)
+
+const (
+	defaultMaxBatchSize  = 100
+	msgItemInternalError = "internal error"
+)
+
+// publicItemErrors are reported to the client as the error of a batch item, without their details.
+// Any other error may come from the database or its driver, and is logged instead.
+var publicItemErrors = []error{
+	ErrInvalidURL,
+	ErrInvalidAlias,
+	ErrInvalidExpiry,
+	ErrInvalidPassword,
+	ErrInvalidRedirectCode,
+	ErrDestinationBlocked,
+	ErrAliasTaken,
+	ErrMixedDomains,
+	ErrMaxRetriesExceeded,
+}
+
+// batchItem is an entry of a batch whose short code is not stored yet.
+type batchItem struct {
+	index int
+	url   *model.URL
+	alias bool
+}
+
+// CreateShortURLs shortens all URLs on the domain with a few batched queries. Invalid entries and taken aliases
+// are reported per item in the result at the same index, and do not fail the other entries.
+func (svc *Service) CreateShortURLs(ctx context.Context, domain string, items []model.URLData) ([]model.BatchResult, error) {
+	if len(items) == 0 {
+		return nil, ErrEmptyBatch
+	}
+
+	if maxSize := svc.maxBatchSize(); len(items) > maxSize {
+		return nil, fmt.Errorf("%w: at most %d URLs are allowed", ErrBatchTooLarge, maxSize)
+	}
+
+	domain, err := svc.resolveDomain(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, _ := OwnerFromContext(ctx)
+	results := make([]model.BatchResult, len(items))
+	// identical deduplicable URLs of the batch share the result of the first one
+	duplicates := make(map[string][]int)
+	var pending []*batchItem
+	for i, data := range items {
+		results[i].URL = data.URL
+		if data.Domain != "" && NormalizeHost(data.Domain) != domain {
+			results[i].Error = svc.itemError(ErrMixedDomains)
+			continue
+		}
+
+		url, err := svc.newURL(ctx, data, owner, domain)
+		if err != nil {
+			results[i].Error = svc.itemError(err)
+			continue
+		}
+
+		if svc.isDeduplicable(data, url) {
+			duplicates[url.LongURL] = append(duplicates[url.LongURL], i)
+			if len(duplicates[url.LongURL]) > 1 {
+				continue
+			}
+		}
+
+		if data.Alias != "" {
+			url.ShortCode = data.Alias
+		}
+
+		pending = append(pending, &batchItem{index: i, url: url, alias: data.Alias != ""})
+	}
+
+	pending, err = svc.reuseExistingURLs(ctx, domain, pending, duplicates, results)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = svc.insertBatch(ctx, domain, pending, results); err != nil {
+		return nil, err
+	}
+
+	for _, indexes := range duplicates {
+		for _, i := range indexes[1:] {
+			results[i].ShortURL = results[indexes[0]].ShortURL
+			results[i].Error = results[indexes[0]].Error
+		}
+	}
+
+	svc.logger.WithFields(logrus.Fields{
+		"size": len(items),
+	}).Debug("Create short URLs in batch")
+
+	return results, nil
+}
+
+// reuseExistingURLs resolves deduplicable items to already stored codes and returns the items left to insert.
+func (svc *Service) reuseExistingURLs(ctx context.Context,
+	domain string,
+	pending []*batchItem,
+	duplicates map[string][]int,
+	results []model.BatchResult,
+) ([]*batchItem, error) {
+	if len(duplicates) == 0 {
+		return pending, nil
+	}
+
+	longURLs := make([]string, 0, len(duplicates))
+	for longURL := range duplicates {
+		longURLs = append(longURLs, longURL)
+	}
+
+	owner, _ := OwnerFromContext(ctx)
+	existing, err := svc.repo.FindByLongURLs(ctx, owner, domain, longURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	shortCodes := make(map[string]string, len(existing))
+	for _, url := range existing {
+		if _, ok := shortCodes[url.LongURL]; !ok {
+			shortCodes[url.LongURL] = url.ShortCode
+		}
+	}
+
+	remaining := pending[:0]
+	for _, item := range pending {
+		indexes, deduplicable := duplicates[item.url.LongURL]
+		shortCode, ok := shortCodes[item.url.LongURL]
+		if ok && deduplicable && indexes[0] == item.index {
+			results[item.index].ShortURL = svc.buildShortURL(domain, shortCode)
+			continue
+		}
+
+		remaining = append(remaining, item)
+	}
+
+	return remaining, nil
+}
+
+// insertBatch assigns codes to the pending items and inserts them. Generated codes that are taken
+// are regenerated and retried up to maxRetries times; taken aliases fail their item only.
+func (svc *Service) insertBatch(ctx context.Context, domain string, pending []*batchItem, results []model.BatchResult) error {
+	for attempt := 0; attempt < maxRetries && len(pending) > 0; attempt++ {
+		shortCodes := make([]string, len(pending))
+		for i, item := range pending {
+			if item.url.ShortCode == "" {
+				shortCode, err := svc.gen.GenerateShortURLCode(ctx)
+				if err != nil {
+					return err
+				}
+
+				item.url.ShortCode = shortCode
+			}
+
+			shortCodes[i] = item.url.ShortCode
+		}
+
+		existing, err := svc.repo.FindExistingShortCodes(ctx, domain, shortCodes)
+		if err != nil {
+			return err
+		}
+
+		taken := make(map[string]struct{}, len(existing)+len(pending))
+		for _, shortCode := range existing {
+			taken[shortCode] = struct{}{}
+		}
+
+		var ready, retry []*batchItem
+		generated, collisions := 0, 0
+		for _, item := range pending {
+			if !item.alias {
+				generated++
+			}
+
+			if _, ok := taken[item.url.ShortCode]; !ok {
+				taken[item.url.ShortCode] = struct{}{}
+				ready = append(ready, item)
+				continue
+			}
+
+			if item.alias {
+				results[item.index].Error = svc.itemError(ErrAliasTaken)
+				continue
+			}
+
+			collisions++
+			item.url.ShortCode = ""
+			retry = append(retry, item)
+		}
+
+		svc.keyspace.record(generated, collisions)
+
+		if len(ready) > 0 {
+			urls := make([]*model.URL, len(ready))
+			readyCodes := make([]string, len(ready))
+			for i, item := range ready {
+				urls[i] = item.url
+				readyCodes[i] = item.url.ShortCode
+			}
+
+			if err = svc.addToFilter(ctx, domain, readyCodes...); err != nil {
+				return err
+			}
+
+			err = svc.repo.CreateBatch(ctx, urls)
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				// a concurrent request took one of the codes in the meantime, check all of them again
+				svc.logger.Debug("failed to create short URLs in batch. Retrying...")
+				pending = append(ready, retry...)
+				continue
+			}
+
+			if err != nil {
+				return err
+			}
+
+			for _, item := range ready {
+				results[item.index].ShortURL = svc.buildShortURL(domain, item.url.ShortCode)
+			}
+
+			svc.forgetMissing(ctx, domain, readyCodes)
+		}
+
+		pending = retry
+	}
+
+	for _, item := range pending {
+		results[item.index].Error = svc.itemError(ErrMaxRetriesExceeded)
+	}
+
+	return nil
+}
+
+// itemError returns the fixed public message of a known item error, or logs the error and hides it.
+func (svc *Service) itemError(err error) string {
+	for _, public := range publicItemErrors {
+		if errors.Is(err, public) {
+			return public.Error()
+		}
+	}
+
+	svc.logger.Errorf("failed to shorten URL in batch. Error: %v", err)
+
+	return msgItemInternalError
+}
+
+func (svc *Service) maxBatchSize() int {
+	if svc.cfg.Shortener.MaxBatchSize > 0 {
+		return svc.cfg.Shortener.MaxBatchSize
+	}
+
+	return defaultMaxBatchSize
+}
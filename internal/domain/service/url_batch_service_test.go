@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	testifyMock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	genMock "github.com/miladbarzideh/shortify/internal/domain/service/mock"
+)
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURLs_Success() {
+	require := suite.Require()
+	input := []model.URLData{
+		{URL: "http://google.com"},
+		{URL: "javascript:alert(1)"},
+		{URL: "http://github.com", Alias: "gh-home"},
+		{URL: "http://gitlab.com", Alias: "taken"},
+		{URL: "http://bitbucket.org"},
+	}
+
+	suite.mockGen.On("GenerateShortURLCode").Return("aaaaa", nil).Once()
+	suite.mockGen.On("GenerateShortURLCode").Return("bbbbb", nil).Once()
+	suite.mockGen.On("GenerateShortURLCode").Return("ccccc", nil).Once()
+	suite.mockRepo.On("FindExistingShortCodes", context.TODO(), "", []string{"aaaaa", "gh-home", "taken", "bbbbb"}).
+		Return([]string{"taken", "bbbbb"}, nil).Once()
+	suite.mockRepo.On("CreateBatch", context.TODO(), testifyMock.MatchedBy(func(urls []*model.URL) bool {
+		return len(urls) == 2 && urls[0].ShortCode == "aaaaa" && urls[1].ShortCode == "gh-home"
+	})).Return(nil).Once()
+	suite.mockRepo.On("FindExistingShortCodes", context.TODO(), "", []string{"ccccc"}).Return([]string{}, nil).Once()
+	suite.mockRepo.On("CreateBatch", context.TODO(), testifyMock.MatchedBy(func(urls []*model.URL) bool {
+		return len(urls) == 1 && urls[0].ShortCode == "ccccc" && urls[0].LongURL == "http://bitbucket.org/"
+	})).Return(nil).Once()
+	results, err := suite.service.CreateShortURLs(context.TODO(), "", input)
+
+	require.NoError(err)
+	require.Len(results, len(input))
+	require.Equal(model.BatchResult{URL: "http://google.com", ShortURL: "localhost:8513/aaaaa"}, results[0])
+	require.Contains(results[1].Error, ErrInvalidURL.Error())
+	require.Equal("localhost:8513/gh-home", results[2].ShortURL)
+	require.Contains(results[3].Error, ErrAliasTaken.Error())
+	require.Empty(results[3].ShortURL)
+	require.Equal("localhost:8513/ccccc", results[4].ShortURL)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", testifyMock.Anything, testifyMock.Anything)
+	suite.mockCacheRepo.AssertCalled(suite.T(), "DeleteMissing", context.TODO(), "", []string{"aaaaa", "gh-home"})
+	suite.mockCacheRepo.AssertCalled(suite.T(), "DeleteMissing", context.TODO(), "", []string{"ccccc"})
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURLs_Deduplicate_Success() {
+	require := suite.Require()
+	suite.service.cfg.Shortener.Deduplicate = true
+	input := []model.URLData{
+		{URL: "http://google.com"},
+		{URL: "http://github.com"},
+		{URL: "HTTP://GitHub.com"},
+		{URL: "http://google.com", ExpiresIn: 60},
+	}
+
+	suite.mockRepo.On("FindByLongURLs", context.TODO(), "", "", testifyMock.MatchedBy(func(longURLs []string) bool {
+		return len(longURLs) == 2
+	})).Return([]model.URL{{LongURL: "http://google.com/", ShortCode: "gclmd"}}, nil).Once()
+	suite.mockGen.On("GenerateShortURLCode").Return("aaaaa", nil).Once()
+	suite.mockGen.On("GenerateShortURLCode").Return("bbbbb", nil).Once()
+	suite.mockRepo.On("FindExistingShortCodes", context.TODO(), "", []string{"aaaaa", "bbbbb"}).Return([]string{}, nil).Once()
+	suite.mockRepo.On("CreateBatch", context.TODO(), testifyMock.Anything).Return(nil).Once()
+	results, err := suite.service.CreateShortURLs(context.TODO(), "", input)
+
+	require.NoError(err)
+	require.Equal("localhost:8513/gclmd", results[0].ShortURL)
+	require.Equal("localhost:8513/aaaaa", results[1].ShortURL)
+	require.Equal("localhost:8513/aaaaa", results[2].ShortURL)
+	require.Equal("localhost:8513/bbbbb", results[3].ShortURL)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURLs_ConcurrentInsert_Success() {
+	require := suite.Require()
+	input := []model.URLData{
+		{URL: "http://google.com"},
+		{URL: "http://github.com", Alias: "gh-home"},
+	}
+
+	suite.mockGen.On("GenerateShortURLCode").Return("aaaaa", nil).Once()
+	suite.mockGen.On("GenerateShortURLCode").Return("bbbbb", nil).Once()
+	suite.mockRepo.On("FindExistingShortCodes", context.TODO(), "", []string{"aaaaa", "gh-home"}).Return([]string{}, nil).Once()
+	suite.mockRepo.On("CreateBatch", context.TODO(), testifyMock.Anything).Return(gorm.ErrDuplicatedKey).Once()
+	suite.mockRepo.On("FindExistingShortCodes", context.TODO(), "", []string{"aaaaa", "gh-home"}).Return([]string{"aaaaa", "gh-home"}, nil).Once()
+	suite.mockRepo.On("FindExistingShortCodes", context.TODO(), "", []string{"bbbbb"}).Return([]string{}, nil).Once()
+	suite.mockRepo.On("CreateBatch", context.TODO(), testifyMock.Anything).Return(nil).Once()
+	results, err := suite.service.CreateShortURLs(context.TODO(), "", input)
+
+	require.NoError(err)
+	require.Equal("localhost:8513/bbbbb", results[0].ShortURL)
+	require.Contains(results[1].Error, ErrAliasTaken.Error())
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURLs_Failure() {
+	require := suite.Require()
+	suite.service.cfg.Shortener.MaxBatchSize = 2
+	testCases := []struct {
+		input       []model.URLData
+		repoErr     error
+		expectedErr error
+	}{
+		{
+			input:       nil,
+			expectedErr: ErrEmptyBatch,
+		},
+		{
+			input:       []model.URLData{{URL: "http://a.com"}, {URL: "http://b.com"}, {URL: "http://c.com"}},
+			expectedErr: ErrBatchTooLarge,
+		},
+		{
+			input:       []model.URLData{{URL: "http://a.com"}},
+			repoErr:     gorm.ErrInvalidDB,
+			expectedErr: gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.repoErr != nil {
+			suite.mockGen.On("GenerateShortURLCode").Return("aaaaa", nil).Once()
+			suite.mockRepo.On("FindExistingShortCodes", context.TODO(), "", testifyMock.Anything).Return(nil, tc.repoErr).Once()
+		}
+
+		results, err := suite.service.CreateShortURLs(context.TODO(), "", tc.input)
+
+		require.ErrorIs(err, tc.expectedErr)
+		require.Nil(results)
+	}
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURLs_ItemErrors() {
+	require := suite.Require()
+	mockPolicy := new(genMock.DestinationPolicy)
+	suite.service.policy = mockPolicy
+	mockPolicy.On("Check", testifyMock.Anything, "http://evil.com/").
+		Return(fmt.Errorf("%w: host 'evil.com' is on the blocklist", ErrDestinationBlocked)).Once()
+	mockPolicy.On("Check", testifyMock.Anything, "http://google.com/").
+		Return(errors.New("pq: connection refused on 10.0.0.5:5432")).Once()
+	mockPolicy.On("Check", testifyMock.Anything, "http://github.com/").Return(nil).Once()
+	input := []model.URLData{
+		{URL: "http://evil.com"},
+		{URL: "http://google.com"},
+		{URL: "http://github.com", Alias: "taken"},
+		{URL: "ftp://github.com"},
+	}
+
+	suite.mockRepo.On("FindExistingShortCodes", context.TODO(), "", []string{"taken"}).Return([]string{"taken"}, nil).Once()
+	results, err := suite.service.CreateShortURLs(context.TODO(), "", input)
+
+	require.NoError(err)
+	require.Equal(ErrDestinationBlocked.Error(), results[0].Error)
+	require.Equal(msgItemInternalError, results[1].Error)
+	require.Equal(ErrAliasTaken.Error(), results[2].Error)
+	require.Equal(ErrInvalidURL.Error(), results[3].Error)
+}
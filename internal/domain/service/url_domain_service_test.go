@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	testifyMock "github.com/stretchr/testify/mock"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+)
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_WithDomain_Success() {
+	require := suite.Require()
+	suite.mockDomains.On("IsRegistered", context.TODO(), "go.example.com").Return(true, nil).Once()
+	suite.mockGen.On("GenerateShortURLCode").Return("gclmd", nil).Once()
+	suite.mockRepo.On("Create", context.TODO(), testifyMock.MatchedBy(func(url *model.URL) bool {
+		return url.Domain == "go.example.com" && url.ShortCode == "gclmd"
+	})).Return(nil).Once()
+	url, err := suite.service.CreateShortURL(context.TODO(), model.URLData{URL: "http://google.com", Domain: "Go.Example.com"})
+
+	require.NoError(err)
+	require.Equal("https://go.example.com/gclmd", url)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_WithDomain_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		registered bool
+		err        error
+		expected   error
+	}{
+		{expected: ErrUnknownDomain},
+		{err: errors.New("connection refused")},
+	}
+
+	for _, tc := range testCases {
+		suite.mockDomains.On("IsRegistered", context.TODO(), "go.example.com").Return(tc.registered, tc.err).Once()
+		url, err := suite.service.CreateShortURL(context.TODO(), model.URLData{URL: "http://google.com", Domain: "go.example.com"})
+
+		require.Error(err)
+		if tc.expected != nil {
+			require.ErrorIs(err, tc.expected)
+		}
+
+		require.Empty(url)
+	}
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", testifyMock.Anything, testifyMock.Anything)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURLs_WithDomain_Success() {
+	require := suite.Require()
+	suite.mockDomains.On("IsRegistered", context.TODO(), "go.example.com").Return(true, nil).Once()
+	suite.mockGen.On("GenerateShortURLCode").Return("aaaaa", nil).Once()
+	suite.mockRepo.On("FindExistingShortCodes", context.TODO(), "go.example.com", []string{"aaaaa"}).Return([]string{}, nil).Once()
+	suite.mockRepo.On("CreateBatch", context.TODO(), testifyMock.MatchedBy(func(urls []*model.URL) bool {
+		return len(urls) == 1 && urls[0].Domain == "go.example.com"
+	})).Return(nil).Once()
+	input := []model.URLData{
+		{URL: "http://a.com", Deduplicate: new(bool)},
+		{URL: "http://b.com", Domain: "other.example.com"},
+	}
+	results, err := suite.service.CreateShortURLs(context.TODO(), "go.example.com", input)
+
+	require.NoError(err)
+	require.Equal("https://go.example.com/aaaaa", results[0].ShortURL)
+	require.Equal(ErrMixedDomains.Error(), results[1].Error)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_GetLongURL_WithDomain_Success() {
+	require := suite.Require()
+	ctx := WithDomain(context.TODO(), "go.example.com")
+	expectedURL := model.URL{LongURL: "http://google.com", Domain: "go.example.com", ShortCode: "G2ogLe"}
+	suite.mockCacheRepo.On("Get", ctx, "go.example.com", "G2ogLe").Return(nil, redis.Nil).Once()
+	suite.mockRepo.On("FindByShortCode", testifyMock.Anything, "go.example.com", "G2ogLe").Return(&expectedURL, nil).Once()
+	suite.mockCacheRepo.On("Set", testifyMock.Anything, &expectedURL).Return(nil).Once()
+	redirect, err := suite.service.GetLongURL(ctx, model.RedirectRequest{ShortCode: "G2ogLe"})
+
+	require.NoError(err)
+	require.Equal(expectedURL.LongURL, redirect.LongURL)
+}
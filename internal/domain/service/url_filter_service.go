@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+)
+
+// filterRebuildBatchSize is the number of short codes read from the database at once while rebuilding the filter.
+const filterRebuildBatchSize = 10000
+
+// ShortCodeLister pages through the stored short codes in ID order.
+type ShortCodeLister interface {
+	ListShortCodes(ctx context.Context, afterID uint, limit int) ([]model.URL, error)
+}
+
+// RebuildableFilter is a CodeFilter that can be filled again from scratch.
+type RebuildableFilter interface {
+	Rebuild(ctx context.Context, load func(add func(items ...string) error) error) error
+}
+
+// FilterService builds the filter of existing short codes from the database.
+type FilterService struct {
+	logger *logrus.Logger
+	repo   ShortCodeLister
+	filter RebuildableFilter
+}
+
+func NewFilterService(logger *logrus.Logger, repo ShortCodeLister, filter RebuildableFilter) *FilterService {
+	return &FilterService{
+		logger: logger,
+		repo:   repo,
+		filter: filter,
+	}
+}
+
+// Rebuild fills the filter with the codes of all stored links and returns their number.
+func (svc *FilterService) Rebuild(ctx context.Context) (int, error) {
+	count := 0
+	err := svc.filter.Rebuild(ctx, func(add func(items ...string) error) error {
+		var afterID uint
+		for {
+			urls, err := svc.repo.ListShortCodes(ctx, afterID, filterRebuildBatchSize)
+			if err != nil {
+				return err
+			}
+
+			if len(urls) == 0 {
+				return nil
+			}
+
+			items := make([]string, len(urls))
+			for i, url := range urls {
+				items[i] = filterItem(url.Domain, url.ShortCode)
+			}
+
+			if err = add(items...); err != nil {
+				return err
+			}
+
+			count += len(urls)
+			afterID = urls[len(urls)-1].ID
+			svc.logger.WithField("count", count).Debug("Add short codes to filter")
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
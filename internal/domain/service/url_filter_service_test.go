@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	genMock "github.com/miladbarzideh/shortify/internal/domain/service/mock"
+	"github.com/miladbarzideh/shortify/pkg/bloom"
+)
+
+type FilterServiceTestSuite struct {
+	suite.Suite
+	service    *FilterService
+	mockRepo   *genMock.Repository
+	mockFilter *genMock.CodeFilter
+}
+
+func (suite *FilterServiceTestSuite) SetupTest() {
+	suite.mockRepo = new(genMock.Repository)
+	suite.mockFilter = new(genMock.CodeFilter)
+	suite.service = NewFilterService(logrus.New(), suite.mockRepo, suite.mockFilter)
+}
+
+func (suite *FilterServiceTestSuite) TestFilterService_Rebuild_Success() {
+	require := suite.Require()
+	suite.mockFilter.On("Rebuild", context.TODO()).Return(nil)
+	suite.mockRepo.On("ListShortCodes", context.TODO(), uint(0), filterRebuildBatchSize).
+		Return([]model.URL{{ID: 3, ShortCode: "abcd"}, {ID: 7, Domain: "go.example.com", ShortCode: "abcd"}}, nil).Once()
+	suite.mockRepo.On("ListShortCodes", context.TODO(), uint(7), filterRebuildBatchSize).Return([]model.URL{}, nil).Once()
+	suite.mockFilter.On("Add", context.TODO(), []string{"abcd", "go.example.com/abcd"}).Return(nil).Once()
+
+	count, err := suite.service.Rebuild(context.TODO())
+
+	require.NoError(err)
+	require.Equal(2, count)
+	suite.mockFilter.AssertExpectations(suite.T())
+}
+
+func (suite *FilterServiceTestSuite) TestFilterService_Rebuild_Failure() {
+	require := suite.Require()
+	suite.mockFilter.On("Rebuild", context.TODO()).Return(nil)
+	suite.mockRepo.On("ListShortCodes", context.TODO(), uint(0), filterRebuildBatchSize).Return(nil, errors.New("connection refused")).Once()
+
+	count, err := suite.service.Rebuild(context.TODO())
+
+	require.Error(err)
+	require.Zero(count)
+}
+
+func TestFilterServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(FilterServiceTestSuite))
+}
+
+func (suite *URLServiceTestSuite) TestURLService_GetLongURL_Filter() {
+	require := suite.Require()
+	testCases := []struct {
+		name          string
+		domain        string
+		item          string
+		contained     bool
+		filterErr     error
+		expectedFound bool
+	}{
+		{name: "rejected", item: "G2ogLe"},
+		{name: "rejected on domain", domain: "go.example.com", item: "go.example.com/G2ogLe"},
+		{name: "contained", item: "G2ogLe", contained: true, expectedFound: true},
+		{name: "not built", item: "G2ogLe", contained: true, filterErr: bloom.ErrNotBuilt, expectedFound: true},
+		{name: "unavailable", item: "G2ogLe", contained: true, filterErr: errors.New("connection refused"), expectedFound: true},
+	}
+
+	for _, tc := range testCases {
+		suite.SetupTest()
+		mockFilter := new(genMock.CodeFilter)
+		suite.service.filter = mockFilter
+		ctx := WithDomain(context.TODO(), tc.domain)
+		mockFilter.On("MightContain", ctx, tc.item).Return(tc.contained, tc.filterErr).Once()
+		suite.mockCacheRepo.On("Get", ctx, tc.domain, "G2ogLe").Return(nil, redis.Nil).Once()
+		suite.mockRepo.On("FindByShortCode", testifyMock.Anything, tc.domain, "G2ogLe").Return(&model.URL{LongURL: "http://google.com/", ShortCode: "G2ogLe"}, nil).Once()
+		suite.mockCacheRepo.On("Set", testifyMock.Anything, testifyMock.Anything).Return(nil).Once()
+
+		redirect, err := suite.service.GetLongURL(ctx, model.RedirectRequest{ShortCode: "G2ogLe"})
+
+		if tc.expectedFound {
+			require.NoError(err, tc.name)
+			require.Equal("http://google.com/", redirect.LongURL, tc.name)
+		} else {
+			require.ErrorIs(err, ErrURLNotFound, tc.name)
+			suite.mockCacheRepo.AssertNotCalled(suite.T(), "Get", ctx, tc.domain, "G2ogLe")
+			suite.mockRepo.AssertNotCalled(suite.T(), "FindByShortCode", testifyMock.Anything, tc.domain, "G2ogLe")
+		}
+	}
+}
+
+func (suite *URLServiceTestSuite) TestURLService_GetLongURL_Filter_FalsePositive() {
+	require := suite.Require()
+	mockFilter := new(genMock.CodeFilter)
+	suite.service.filter = mockFilter
+	mockFilter.On("MightContain", context.TODO(), "G2ogLe").Return(true, nil).Once()
+	suite.mockCacheRepo.On("Get", context.TODO(), "", "G2ogLe").Return(nil, redis.Nil).Once()
+	suite.mockRepo.On("FindByShortCode", testifyMock.Anything, "", "G2ogLe").Return(nil, gorm.ErrRecordNotFound).Once()
+	suite.mockCacheRepo.On("SetMissing", testifyMock.Anything, "", "G2ogLe").Return(nil).Once()
+
+	_, err := suite.service.GetLongURL(context.TODO(), model.RedirectRequest{ShortCode: "G2ogLe"})
+
+	require.ErrorIs(err, ErrURLNotFound)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_AddsToFilter() {
+	require := suite.Require()
+	mockFilter := new(genMock.CodeFilter)
+	suite.service.filter = mockFilter
+	mockFilter.On("Add", context.TODO(), []string{"aaaaa"}).Return(nil).Once()
+	mockFilter.On("Add", context.TODO(), []string{"bbbbb"}).Return(nil).Once()
+	suite.mockGen.On("GenerateShortURLCode").Return("aaaaa", nil).Once()
+	suite.mockGen.On("GenerateShortURLCode").Return("bbbbb", nil).Once()
+	suite.mockRepo.On("Create", context.TODO(), testifyMock.Anything).Return(gorm.ErrDuplicatedKey).Once()
+	suite.mockRepo.On("Create", context.TODO(), testifyMock.Anything).Return(nil).Once()
+
+	_, err := suite.service.CreateShortURL(context.TODO(), model.URLData{URL: "http://google.com"})
+
+	require.NoError(err)
+	mockFilter.AssertExpectations(suite.T())
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_AddToFilter_Failure() {
+	require := suite.Require()
+	mockFilter := new(genMock.CodeFilter)
+	suite.service.filter = mockFilter
+	mockFilter.On("Add", context.TODO(), []string{"spring-sale"}).Return(errors.New("connection refused")).Once()
+
+	_, err := suite.service.CreateShortURL(context.TODO(), model.URLData{URL: "http://google.com", Alias: "spring-sale"})
+
+	require.Error(err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", testifyMock.Anything, testifyMock.Anything)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURLs_AddsToFilter() {
+	require := suite.Require()
+	mockFilter := new(genMock.CodeFilter)
+	suite.service.filter = mockFilter
+	ctx := WithDomain(context.TODO(), "go.example.com")
+	mockFilter.On("Add", ctx, []string{"go.example.com/aaaaa", "go.example.com/gh-home"}).Return(nil).Once()
+	suite.mockGen.On("GenerateShortURLCode").Return("aaaaa", nil).Once()
+	suite.mockRepo.On("FindExistingShortCodes", ctx, "go.example.com", []string{"aaaaa", "gh-home"}).Return([]string{}, nil).Once()
+	suite.mockRepo.On("CreateBatch", ctx, testifyMock.Anything).Return(nil).Once()
+
+	results, err := suite.service.CreateShortURLs(ctx, "", []model.URLData{{URL: "http://google.com"}, {URL: "http://github.com", Alias: "gh-home"}})
+
+	require.NoError(err)
+	require.Equal("https://go.example.com/aaaaa", results[0].ShortURL)
+	mockFilter.AssertExpectations(suite.T())
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+const (
+	defaultGrowthWindow       = 1000
+	defaultCollisionThreshold = 0.1
+	defaultMaxFillRatio       = 0.5
+	defaultMaxCodeLength      = 10
+)
+
+// keyspaceMonitor grows the length of generated codes before their keyspace saturates. It tracks
+// the share of generated codes that were already taken over a window of attempts, and after each
+// window the share of the 62^length codes in use. Crossing either threshold adds one character.
+type keyspaceMonitor struct {
+	logger *logrus.Logger
+	cfg    infra.Growth
+	gen    ResizableGenerator
+	repo   URLRepository
+	pool   WorkerPool
+
+	mu            sync.Mutex
+	attempts      int
+	collisions    int
+	collisionRate float64
+	fillRatio     float64
+}
+
+func newKeyspaceMonitor(
+	logger *logrus.Logger,
+	cfg infra.Growth,
+	gen ResizableGenerator,
+	repo URLRepository,
+	pool WorkerPool,
+	meter metric.Meter,
+) *keyspaceMonitor {
+	if cfg.Window <= 0 {
+		cfg.Window = defaultGrowthWindow
+	}
+
+	if cfg.CollisionThreshold <= 0 {
+		cfg.CollisionThreshold = defaultCollisionThreshold
+	}
+
+	if cfg.MaxFillRatio <= 0 {
+		cfg.MaxFillRatio = defaultMaxFillRatio
+	}
+
+	if cfg.MaxCodeLength <= 0 {
+		cfg.MaxCodeLength = defaultMaxCodeLength
+	}
+
+	m := &keyspaceMonitor{
+		logger: logger,
+		cfg:    cfg,
+		gen:    gen,
+		repo:   repo,
+		pool:   pool,
+	}
+
+	_, err := meter.Int64ObservableGauge("shortener.code_length",
+		metric.WithDescription("length of the generated short codes"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			o.Observe(int64(m.gen.Length()))
+			return nil
+		}),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = meter.Float64ObservableGauge("shortener.collision_rate",
+		metric.WithDescription("share of generated short codes that were taken in the last window"),
+		metric.WithFloat64Callback(func(_ context.Context, o metric.Float64Observer) error {
+			rate, _ := m.rates()
+			o.Observe(rate)
+			return nil
+		}),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = meter.Float64ObservableGauge("shortener.fill_ratio",
+		metric.WithDescription("share of the short codes of the current length in use"),
+		metric.WithFloat64Callback(func(_ context.Context, o metric.Float64Observer) error {
+			_, ratio := m.rates()
+			o.Observe(ratio)
+			return nil
+		}),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// record adds the outcome of storing generated codes. A nil monitor, when growth is disabled, ignores it.
+func (m *keyspaceMonitor) record(attempts int, collisions int) {
+	if m == nil || attempts == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	m.attempts += attempts
+	m.collisions += collisions
+	if m.attempts < m.cfg.Window {
+		m.mu.Unlock()
+		return
+	}
+
+	m.collisionRate = float64(m.collisions) / float64(m.attempts)
+	m.attempts, m.collisions = 0, 0
+	rate := m.collisionRate
+	m.mu.Unlock()
+
+	length := m.gen.Length()
+	if rate > m.cfg.CollisionThreshold {
+		m.grow(length, "collision rate", rate)
+		return
+	}
+
+	// counting the codes hits the database, so it runs once per window and off the request path
+	if err := m.pool.Submit(func(ctx context.Context) {
+		m.checkFillRatio(ctx, length)
+	}); err != nil {
+		m.logger.Debugf("skipping keyspace fill ratio check: %v", err)
+	}
+}
+
+func (m *keyspaceMonitor) checkFillRatio(ctx context.Context, length int) {
+	ratio, err := m.measureFillRatio(ctx, length)
+	if err != nil {
+		m.logger.Errorf("failed to count short codes of length %d: %v", length, err)
+		return
+	}
+
+	if ratio > m.cfg.MaxFillRatio {
+		m.grow(length, "fill ratio", ratio)
+	}
+}
+
+// catchUp grows the code length until the codes of the current length fill at most max_fill_ratio of their
+// keyspace. A grown length is only kept in memory, so an instance that starts from the configured length
+// catches up before serving, rather than failing on collisions until a window has passed.
+// A nil monitor, when growth is disabled, does nothing.
+func (m *keyspaceMonitor) catchUp(ctx context.Context) error {
+	if m == nil {
+		return nil
+	}
+
+	for {
+		length := m.gen.Length()
+		ratio, err := m.measureFillRatio(ctx, length)
+		if err != nil {
+			return err
+		}
+
+		if ratio <= m.cfg.MaxFillRatio {
+			return nil
+		}
+
+		m.grow(length, "fill ratio", ratio)
+		if length >= m.cfg.MaxCodeLength {
+			return nil
+		}
+	}
+}
+
+// measureFillRatio returns the share of the 62^length codes in use.
+func (m *keyspaceMonitor) measureFillRatio(ctx context.Context, length int) (float64, error) {
+	count, err := m.repo.CountByCodeLength(ctx, length)
+	if err != nil {
+		return 0, err
+	}
+
+	ratio := float64(count) / math.Pow(62, float64(length))
+	m.mu.Lock()
+	m.fillRatio = ratio
+	m.mu.Unlock()
+
+	return ratio, nil
+}
+
+// grow adds one character to codes of the given length. Concurrent callers that observed the same
+// length grow it only once.
+func (m *keyspaceMonitor) grow(length int, reason string, value float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.gen.Length() != length {
+		return
+	}
+
+	if length >= m.cfg.MaxCodeLength {
+		m.logger.Warnf("short code keyspace is crowded (%s %.2f) but code length is already at its maximum %d", reason, value, length)
+		return
+	}
+
+	m.gen.SetLength(length + 1)
+	m.fillRatio = 0
+	m.logger.WithFields(logrus.Fields{
+		"reason": reason,
+		"value":  value,
+		"length": length + 1,
+	}).Warn("Grow short code length")
+}
+
+func (m *keyspaceMonitor) rates() (float64, float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.collisionRate, m.fillRatio
+}
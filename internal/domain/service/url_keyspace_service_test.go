@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	genMock "github.com/miladbarzideh/shortify/internal/domain/service/mock"
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+type KeyspaceMonitorTestSuite struct {
+	suite.Suite
+	mockRepo *genMock.Repository
+	mockGen  *genMock.Generator
+	mockPool *genMock.WorkerPool
+	cfg      infra.Growth
+}
+
+func (suite *KeyspaceMonitorTestSuite) SetupTest() {
+	suite.mockRepo = new(genMock.Repository)
+	suite.mockGen = new(genMock.Generator)
+	suite.mockPool = new(genMock.WorkerPool)
+	suite.mockPool.On("Submit").Return(nil)
+	suite.cfg = infra.Growth{
+		Enabled:            true,
+		CollisionThreshold: 0.1,
+		MaxFillRatio:       0.5,
+		Window:             10,
+		MaxCodeLength:      6,
+	}
+}
+
+func (suite *KeyspaceMonitorTestSuite) newMonitor() *keyspaceMonitor {
+	meter := infra.NOOPTelemetry.MeterProvider.Meter("test")
+	return newKeyspaceMonitor(logrus.New(), suite.cfg, suite.mockGen, suite.mockRepo, suite.mockPool, meter)
+}
+
+func (suite *KeyspaceMonitorTestSuite) TestKeyspaceMonitor_Record() {
+	require := suite.Require()
+	testCases := []struct {
+		name       string
+		attempts   int
+		collisions int
+		count      int64
+		length     int
+		grow       bool
+	}{
+		{name: "window not complete", attempts: 9, collisions: 9, length: 5},
+		{name: "collision rate over threshold", attempts: 10, collisions: 2, length: 5, grow: true},
+		{name: "keyspace half used", attempts: 10, count: 500_000_000, length: 5, grow: true},
+		{name: "keyspace has room", attempts: 10, collisions: 1, count: 1000, length: 5},
+		{name: "already at max length", attempts: 10, collisions: 10, length: 6},
+	}
+
+	for _, tc := range testCases {
+		suite.SetupTest()
+		suite.mockGen.On("Length").Return(tc.length)
+		suite.mockGen.On("SetLength", tc.length+1).Return()
+		suite.mockRepo.On("CountByCodeLength", testifyMock.Anything, tc.length).Return(tc.count, nil)
+		monitor := suite.newMonitor()
+
+		monitor.record(tc.attempts, tc.collisions)
+
+		if tc.grow {
+			suite.mockGen.AssertCalled(suite.T(), "SetLength", tc.length+1)
+		} else {
+			suite.mockGen.AssertNotCalled(suite.T(), "SetLength", tc.length+1)
+		}
+		collisionRate, _ := monitor.rates()
+		if tc.attempts >= suite.cfg.Window {
+			require.InDelta(float64(tc.collisions)/float64(tc.attempts), collisionRate, 1e-9, tc.name)
+		} else {
+			require.Zero(collisionRate, tc.name)
+		}
+	}
+}
+
+func (suite *KeyspaceMonitorTestSuite) TestKeyspaceMonitor_CountFailure() {
+	suite.mockGen.On("Length").Return(5)
+	suite.mockRepo.On("CountByCodeLength", testifyMock.Anything, 5).Return(int64(0), errors.New("connection refused"))
+	monitor := suite.newMonitor()
+
+	monitor.record(10, 0)
+
+	suite.mockGen.AssertNotCalled(suite.T(), "SetLength", testifyMock.Anything)
+}
+
+func (suite *KeyspaceMonitorTestSuite) TestKeyspaceMonitor_CatchUp() {
+	require := suite.Require()
+	testCases := []struct {
+		name           string
+		counts         map[int]int64
+		length         int
+		expectedLength int
+	}{
+		{name: "keyspace has room", counts: map[int]int64{4: 1000}, length: 4, expectedLength: 4},
+		{name: "grown before restart", counts: map[int]int64{4: 10_000_000, 5: 1000}, length: 4, expectedLength: 5},
+		{name: "grown twice", counts: map[int]int64{4: 10_000_000, 5: 500_000_000, 6: 0}, length: 4, expectedLength: 6},
+		{name: "already at max length", counts: map[int]int64{6: 40_000_000_000}, length: 6, expectedLength: 6},
+	}
+
+	for _, tc := range testCases {
+		suite.SetupTest()
+		gen := &resizableGenerator{length: tc.length}
+		for codeLength, count := range tc.counts {
+			suite.mockRepo.On("CountByCodeLength", context.TODO(), codeLength).Return(count, nil)
+		}
+		meter := infra.NOOPTelemetry.MeterProvider.Meter("test")
+		monitor := newKeyspaceMonitor(logrus.New(), suite.cfg, gen, suite.mockRepo, suite.mockPool, meter)
+
+		err := monitor.catchUp(context.TODO())
+
+		require.NoError(err, tc.name)
+		require.Equal(tc.expectedLength, gen.Length(), tc.name)
+	}
+}
+
+func (suite *KeyspaceMonitorTestSuite) TestKeyspaceMonitor_CatchUp_Failure() {
+	require := suite.Require()
+	suite.mockGen.On("Length").Return(5)
+	suite.mockRepo.On("CountByCodeLength", context.TODO(), 5).Return(int64(0), errors.New("connection refused"))
+	monitor := suite.newMonitor()
+
+	err := monitor.catchUp(context.TODO())
+
+	require.Error(err)
+	suite.mockGen.AssertNotCalled(suite.T(), "SetLength", testifyMock.Anything)
+	require.NoError((*keyspaceMonitor)(nil).catchUp(context.TODO()))
+}
+
+func (suite *KeyspaceMonitorTestSuite) TestKeyspaceMonitor_CreateShortURL_Grow() {
+	require := suite.Require()
+	suite.mockGen.On("GenerateShortURLCode").Return("aaaaa", nil).Once()
+	suite.mockGen.On("GenerateShortURLCode").Return("bbbbb", nil).Once()
+	suite.mockGen.On("Length").Return(5)
+	suite.mockGen.On("SetLength", 6).Return()
+	suite.mockRepo.On("Create", context.TODO(), testifyMock.Anything).Return(gorm.ErrDuplicatedKey).Once()
+	suite.mockRepo.On("Create", context.TODO(), testifyMock.Anything).Return(nil).Once()
+	mockCacheRepo := new(genMock.CacheRepository)
+	mockCacheRepo.On("DeleteMissing", context.TODO(), "", []string{"bbbbb"}).Return(nil)
+	cfg := infra.Config{}
+	cfg.Server.Address = "localhost:8513"
+	cfg.Shortener.Growth = suite.cfg
+	cfg.Shortener.Growth.Window = 2
+	svc := NewService(logrus.New(), &cfg, suite.mockRepo, mockCacheRepo, nil, nil, nil, nil, nil, nil, suite.mockGen, suite.mockPool, infra.NOOPTelemetry)
+
+	url, err := svc.CreateShortURL(context.TODO(), model.URLData{URL: "http://google.com"})
+
+	require.NoError(err)
+	require.Equal("localhost:8513/bbbbb", url)
+	suite.mockGen.AssertCalled(suite.T(), "SetLength", 6)
+}
+
+// resizableGenerator keeps the length it is given, unlike the mock whose length is fixed by its expectations.
+type resizableGenerator struct {
+	length int
+}
+
+func (g *resizableGenerator) GenerateShortURLCode(context.Context) (string, error) {
+	return strings.Repeat("a", g.length), nil
+}
+
+func (g *resizableGenerator) Length() int {
+	return g.length
+}
+
+func (g *resizableGenerator) SetLength(length int) {
+	g.length = length
+}
+
+func TestKeyspaceMonitorTestSuite(t *testing.T) {
+	suite.Run(t, new(KeyspaceMonitorTestSuite))
+}
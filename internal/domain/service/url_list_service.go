@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+)
+
+var (
+	ErrInvalidListQuery = errors.New("invalid list query")
+	ErrOwnerRequired    = errors.New("owner required")
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+	ownerMe          = "me"
+	sortCreatedAt    = "created_at"
+	// sortCreatedAtDesc lists the newest links first, the default
+	sortCreatedAtDesc = "-created_at"
+	maxDomainFilter   = 255
+)
+
+// ListURLs returns a page of the links of the caller, keyed by an opaque cursor.
+func (svc *Service) ListURLs(ctx context.Context, query model.URLListQuery) (*model.URLPage, error) {
+	owner, ok := OwnerFromContext(ctx)
+	if !ok {
+		return nil, ErrOwnerRequired
+	}
+
+	filter, err := newURLFilter(owner, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// one more row than requested tells whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+	urls, err := svc.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.URLPage{Items: make([]model.URLInfo, 0, min(len(urls), limit))}
+	for i := range urls {
+		if i == limit {
+			page.NextCursor = encodeCursor(urls[i-1].ID)
+			break
+		}
+
+		page.Items = append(page.Items, *svc.toURLInfo(&urls[i]))
+	}
+
+	return page, nil
+}
+
+func newURLFilter(owner string, query model.URLListQuery) (model.URLFilter, error) {
+	filter := model.URLFilter{
+		OwnerID: owner,
+		Domain:  query.Domain,
+		Limit:   query.Limit,
+	}
+
+	// only the links of the caller can be listed for now
+	if query.Owner != "" && query.Owner != ownerMe && query.Owner != owner {
+		return filter, fmt.Errorf("%w: owner must be '%s'", ErrInvalidListQuery, ownerMe)
+	}
+
+	if len(query.Domain) > maxDomainFilter {
+		return filter, fmt.Errorf("%w: domain must be at most %d characters", ErrInvalidListQuery, maxDomainFilter)
+	}
+
+	switch query.Sort {
+	case sortCreatedAt:
+		filter.Ascending = true
+	case "", sortCreatedAtDesc:
+	default:
+		return filter, fmt.Errorf("%w: sort must be '%s' or '%s'", ErrInvalidListQuery, sortCreatedAt, sortCreatedAtDesc)
+	}
+
+	switch {
+	case query.Limit == 0:
+		filter.Limit = defaultListLimit
+	case query.Limit < 0 || query.Limit > maxListLimit:
+		return filter, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidListQuery, maxListLimit)
+	}
+
+	if query.Cursor != "" {
+		id, err := decodeCursor(query.Cursor)
+		if err != nil {
+			return filter, fmt.Errorf("%w: malformed cursor", ErrInvalidListQuery)
+		}
+
+		filter.AfterID = id
+	}
+
+	return filter, nil
+}
+
+// encodeCursor hides the keyset of the next page, so clients do not depend on its format.
+func encodeCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+func decodeCursor(cursor string) (uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := strconv.ParseUint(string(raw), 10, 0)
+	if err != nil || id == 0 {
+		return 0, errors.New("invalid cursor")
+	}
+
+	return uint(id), nil
+}
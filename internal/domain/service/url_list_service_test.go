@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+
+	testifyMock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+)
+
+func (suite *URLServiceTestSuite) TestURLService_ListURLs_Success() {
+	require := suite.Require()
+	ctx := WithOwner(context.TODO(), "alice")
+	testCases := []struct {
+		query          model.URLListQuery
+		expectedFilter model.URLFilter
+		rows           []model.URL
+		expectedCodes  []string
+		expectedCursor string
+	}{
+		{
+			query:          model.URLListQuery{Owner: "me", Limit: 2},
+			expectedFilter: model.URLFilter{OwnerID: "alice", Limit: 3},
+			rows:           []model.URL{{ID: 9, ShortCode: "c"}, {ID: 7, ShortCode: "b"}, {ID: 3, ShortCode: "a"}},
+			expectedCodes:  []string{"c", "b"},
+			expectedCursor: encodeCursor(7),
+		},
+		{
+			query:          model.URLListQuery{Domain: "github", Sort: "created_at", Cursor: encodeCursor(7)},
+			expectedFilter: model.URLFilter{OwnerID: "alice", Domain: "github", AfterID: 7, Ascending: true, Limit: defaultListLimit + 1},
+			rows:           []model.URL{{ID: 9, ShortCode: "c"}},
+			expectedCodes:  []string{"c"},
+		},
+		{
+			query:          model.URLListQuery{Owner: "alice", Sort: "-created_at"},
+			expectedFilter: model.URLFilter{OwnerID: "alice", Limit: defaultListLimit + 1},
+			expectedCodes:  []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.mockRepo.On("List", ctx, tc.expectedFilter).Return(tc.rows, nil).Once()
+		page, err := suite.service.ListURLs(ctx, tc.query)
+
+		require.NoError(err)
+		codes := make([]string, 0, len(page.Items))
+		for _, item := range page.Items {
+			codes = append(codes, item.ShortCode)
+		}
+
+		require.Equal(tc.expectedCodes, codes)
+		require.Equal(tc.expectedCursor, page.NextCursor)
+	}
+}
+
+func (suite *URLServiceTestSuite) TestURLService_ListURLs_Failure() {
+	require := suite.Require()
+	ctx := WithOwner(context.TODO(), "alice")
+	testCases := []struct {
+		ctx         context.Context
+		query       model.URLListQuery
+		expectedErr error
+	}{
+		{
+			ctx:         context.TODO(),
+			expectedErr: ErrOwnerRequired,
+		},
+		{
+			ctx:         ctx,
+			query:       model.URLListQuery{Owner: "bob"},
+			expectedErr: ErrInvalidListQuery,
+		},
+		{
+			ctx:         ctx,
+			query:       model.URLListQuery{Sort: "short_code"},
+			expectedErr: ErrInvalidListQuery,
+		},
+		{
+			ctx:         ctx,
+			query:       model.URLListQuery{Limit: maxListLimit + 1},
+			expectedErr: ErrInvalidListQuery,
+		},
+		{
+			ctx:         ctx,
+			query:       model.URLListQuery{Cursor: "not a cursor"},
+			expectedErr: ErrInvalidListQuery,
+		},
+	}
+
+	for _, tc := range testCases {
+		page, err := suite.service.ListURLs(tc.ctx, tc.query)
+
+		require.ErrorIs(err, tc.expectedErr)
+		require.Nil(page)
+	}
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "List", testifyMock.Anything, testifyMock.Anything)
+
+	suite.mockRepo.On("List", ctx, testifyMock.Anything).Return(nil, gorm.ErrInvalidDB).Once()
+	page, err := suite.service.ListURLs(ctx, model.URLListQuery{})
+
+	require.ErrorIs(err, gorm.ErrInvalidDB)
+	require.Nil(page)
+}
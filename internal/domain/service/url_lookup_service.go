@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+)
+
+const (
+	// lockPollInterval is how often an instance waiting for another one to fill the cache checks it.
+	lockPollInterval = 20 * time.Millisecond
+	defaultLockTTL   = 2 * time.Second
+	defaultLockWait  = 500 * time.Millisecond
+)
+
+// LookupLock serializes the database lookups of a short code across instances.
+type LookupLock interface {
+	Acquire(ctx context.Context, key string, ttl time.Duration) (string, bool, error)
+	Release(ctx context.Context, key string, token string) error
+}
+
+// lookupURL reads a code missing from the cache from the database. Concurrent lookups of the same code
+// on this instance share a single query, and with a lock across instances too. A request stops waiting
+// for the lookup when it is canceled.
+func (svc *Service) lookupURL(ctx context.Context, domain string, shortCode string) (*model.URL, error) {
+	leader := false
+	results := svc.lookups.DoChan(filterItem(domain, shortCode), func() (interface{}, error) {
+		leader = true
+		// the query is shared, so it must not be canceled with the request that happens to run it
+		ctx := context.WithoutCancel(ctx)
+		if svc.lock != nil {
+			return svc.lookupURLWithLock(ctx, domain, shortCode)
+		}
+
+		return svc.loadURL(ctx, domain, shortCode, false)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-results:
+		if result.Shared && !leader {
+			svc.lookupsCoalesced.Inc(ctx)
+		}
+
+		if result.Err != nil {
+			return nil, result.Err
+		}
+
+		return result.Val.(*model.URL), nil
+	}
+}
+
+// lookupURLWithLock reads the code from the database while holding its lock, or else waits for the holder
+// to fill the cache until ctx is done. It reads the database anyway when the lock fails or the wait times out.
+func (svc *Service) lookupURLWithLock(ctx context.Context, domain string, shortCode string) (*model.URL, error) {
+	ttl, wait := svc.lockConfig()
+	key := filterItem(domain, shortCode)
+	token, acquired, err := svc.lock.Acquire(ctx, key, ttl)
+	if err != nil {
+		svc.logger.Errorf("failed to lock lookup of short URL '%s'. Error: %v", shortCode, err)
+		return svc.loadURL(ctx, domain, shortCode, false)
+	}
+
+	if acquired {
+		defer func() {
+			if err := svc.lock.Release(ctx, key, token); err != nil {
+				svc.logger.Errorf("failed to unlock lookup of short URL '%s'. Error: %v", shortCode, err)
+			}
+		}()
+
+		return svc.loadURL(ctx, domain, shortCode, true)
+	}
+
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+	poll := time.NewTicker(lockPollInterval)
+	defer poll.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline.C:
+			return svc.loadURL(ctx, domain, shortCode, false)
+		case <-poll.C:
+			url, err := svc.cacheRepo.Get(ctx, domain, shortCode)
+			if err == nil {
+				svc.lookupsLockCoalesced.Inc(ctx)
+				return url, nil
+			}
+
+			if errors.Is(err, model.ErrCachedMissing) {
+				svc.lookupsLockCoalesced.Inc(ctx)
+				return nil, ErrURLNotFound
+			}
+		}
+	}
+}
+
+// lockConfig returns the TTL and the wait of the lookup lock, with defaults for those not set. A lock must
+// expire, or the codes locked by an instance that died would make the others wait on every miss.
+func (svc *Service) lockConfig() (time.Duration, time.Duration) {
+	ttl, wait := defaultLockTTL, defaultLockWait
+	if svc.cfg.Cache.Lock.TTL > 0 {
+		ttl = svc.cfg.Cache.Lock.TTL
+	}
+
+	if svc.cfg.Cache.Lock.Wait > 0 {
+		wait = svc.cfg.Cache.Lock.Wait
+	}
+
+	return ttl, wait
+}
+
+// loadURL reads the URL from the database and caches it, or caches that it is missing. The cache is written
+// in the background, or right away when other instances wait for it.
+func (svc *Service) loadURL(ctx context.Context, domain string, shortCode string, syncCache bool) (*model.URL, error) {
+	url, err := svc.repo.FindByShortCode(ctx, domain, shortCode)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		if !syncCache {
+			svc.cacheMissing(domain, shortCode)
+		} else if err = svc.cacheRepo.SetMissing(ctx, domain, shortCode); err != nil {
+			svc.logger.Errorf("failed to cache missing short URL '%s'. Error: %v", shortCode, err)
+		}
+
+		return nil, ErrURLNotFound
+	}
+
+	// links that can not be redirected to are not cached
+	if checkServable(url, time.Now()) != nil {
+		return url, nil
+	}
+
+	if !syncCache {
+		svc.warmUpCache(url)
+	} else if err = svc.cacheRepo.Set(ctx, url); err != nil {
+		svc.logger.Errorf("failed to cache short URL '%s'. Error: %v", url.ShortCode, err)
+	}
+
+	return url, nil
+}
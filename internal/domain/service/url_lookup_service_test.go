@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	testifyMock "github.com/stretchr/testify/mock"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	genMock "github.com/miladbarzideh/shortify/internal/domain/service/mock"
+)
+
+func (suite *URLServiceTestSuite) TestURLService_GetLongURL_Coalesced() {
+	require := suite.Require()
+	url := &model.URL{LongURL: "http://google.com/", ShortCode: "G2ogLe"}
+	release := make(chan time.Time)
+	suite.mockCacheRepo.On("Get", context.TODO(), "", "G2ogLe").Return(nil, redis.Nil)
+	suite.mockCacheRepo.On("Set", testifyMock.Anything, url).Return(nil)
+	suite.mockRepo.On("FindByShortCode", testifyMock.Anything, "", "G2ogLe").WaitUntil(release).Return(url, nil)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := suite.service.GetLongURL(context.TODO(), model.RedirectRequest{ShortCode: "G2ogLe"})
+			errs <- err
+		}()
+	}
+
+	// let every request join the lookup in flight
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(err)
+	}
+	suite.mockRepo.AssertNumberOfCalls(suite.T(), "FindByShortCode", 1)
+	suite.mockCacheRepo.AssertNumberOfCalls(suite.T(), "Set", 1)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_GetLongURL_Lock() {
+	require := suite.Require()
+	url := &model.URL{LongURL: "http://google.com/", ShortCode: "G2ogLe"}
+	testCases := []struct {
+		name       string
+		acquired   bool
+		lockErr    error
+		cachedOnce bool // the holder of the lock fills the cache while waiting
+		expectedDB bool
+	}{
+		{name: "acquired", acquired: true, expectedDB: true},
+		{name: "held, cache filled", cachedOnce: true},
+		{name: "held, wait timed out", expectedDB: true},
+		{name: "lock failure", lockErr: errors.New("connection refused"), expectedDB: true},
+	}
+
+	for _, tc := range testCases {
+		suite.SetupTest()
+		mockLock := new(genMock.LookupLock)
+		suite.service.lock = mockLock
+		suite.service.cfg.Cache.Lock.TTL = 2 * time.Second
+		suite.service.cfg.Cache.Lock.Wait = 100 * time.Millisecond
+		token := ""
+		if tc.acquired {
+			token = "token"
+		}
+		mockLock.On("Acquire", testifyMock.Anything, "G2ogLe", 2*time.Second).Return(token, tc.acquired, tc.lockErr).Once()
+		mockLock.On("Release", testifyMock.Anything, "G2ogLe", "token").Return(nil).Once()
+		suite.mockCacheRepo.On("Get", testifyMock.Anything, "", "G2ogLe").Return(nil, redis.Nil).Once()
+		if tc.cachedOnce {
+			suite.mockCacheRepo.On("Get", testifyMock.Anything, "", "G2ogLe").Return(url, nil).Once()
+		} else {
+			suite.mockCacheRepo.On("Get", testifyMock.Anything, "", "G2ogLe").Return(nil, redis.Nil)
+		}
+		suite.mockCacheRepo.On("Set", testifyMock.Anything, url).Return(nil).Once()
+		suite.mockRepo.On("FindByShortCode", testifyMock.Anything, "", "G2ogLe").Return(url, nil).Once()
+
+		redirect, err := suite.service.GetLongURL(context.TODO(), model.RedirectRequest{ShortCode: "G2ogLe"})
+
+		require.NoError(err, tc.name)
+		require.Equal(url.LongURL, redirect.LongURL, tc.name)
+		if tc.expectedDB {
+			suite.mockRepo.AssertCalled(suite.T(), "FindByShortCode", testifyMock.Anything, "", "G2ogLe")
+			suite.mockCacheRepo.AssertCalled(suite.T(), "Set", testifyMock.Anything, url)
+		} else {
+			suite.mockRepo.AssertNotCalled(suite.T(), "FindByShortCode", testifyMock.Anything, "", "G2ogLe")
+		}
+		if tc.acquired {
+			mockLock.AssertCalled(suite.T(), "Release", testifyMock.Anything, "G2ogLe", "token")
+		} else {
+			mockLock.AssertNotCalled(suite.T(), "Release", testifyMock.Anything, "G2ogLe", "token")
+		}
+	}
+}
+
+func (suite *URLServiceTestSuite) TestURLService_GetLongURL_Lock_Defaults() {
+	require := suite.Require()
+	mockLock := new(genMock.LookupLock)
+	suite.service.lock = mockLock
+	url := &model.URL{LongURL: "http://google.com/", ShortCode: "G2ogLe"}
+	mockLock.On("Acquire", testifyMock.Anything, "G2ogLe", defaultLockTTL).Return("token", true, nil).Once()
+	mockLock.On("Release", testifyMock.Anything, "G2ogLe", "token").Return(nil).Once()
+	suite.mockCacheRepo.On("Get", testifyMock.Anything, "", "G2ogLe").Return(nil, redis.Nil).Once()
+	suite.mockCacheRepo.On("Set", testifyMock.Anything, url).Return(nil).Once()
+	suite.mockRepo.On("FindByShortCode", testifyMock.Anything, "", "G2ogLe").Return(url, nil).Once()
+
+	_, err := suite.service.GetLongURL(context.TODO(), model.RedirectRequest{ShortCode: "G2ogLe"})
+
+	require.NoError(err)
+	mockLock.AssertExpectations(suite.T())
+}
+
+func (suite *URLServiceTestSuite) TestURLService_GetLongURL_Canceled() {
+	require := suite.Require()
+	url := &model.URL{LongURL: "http://google.com/", ShortCode: "G2ogLe"}
+	release := make(chan time.Time)
+	defer close(release)
+	ctx, cancel := context.WithCancel(context.TODO())
+	suite.mockCacheRepo.On("Get", ctx, "", "G2ogLe").Return(nil, redis.Nil).Once()
+	suite.mockCacheRepo.On("Set", testifyMock.Anything, url).Return(nil).Maybe()
+	suite.mockRepo.On("FindByShortCode", testifyMock.Anything, "", "G2ogLe").WaitUntil(release).Return(url, nil).Once()
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := suite.service.GetLongURL(ctx, model.RedirectRequest{ShortCode: "G2ogLe"})
+		errs <- err
+	}()
+	cancel()
+
+	select {
+	case err := <-errs:
+		require.ErrorIs(err, context.Canceled)
+	case <-time.After(time.Second):
+		require.Fail("the canceled request kept waiting for the lookup")
+	}
+}
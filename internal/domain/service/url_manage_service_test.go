@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	testifyMock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+)
+
+const (
+	testActor = "203.0.113.7"
+	testOwner = "alice"
+)
+
+// ownerCtx is the context of a request authenticated with a key of testOwner.
+var ownerCtx = WithOwner(context.TODO(), testOwner)
+
+func (suite *URLServiceTestSuite) TestURLService_GetURLInfo_Success() {
+	require := suite.Require()
+	url := model.URL{ID: 1, LongURL: "http://google.com/", OriginalURL: "http://Google.com", ShortCode: "G2ogLe"}
+	suite.mockRepo.On("FindByShortCode", context.TODO(), "", url.ShortCode).Return(&url, nil).Once()
+	info, err := suite.service.GetURLInfo(context.TODO(), url.ShortCode)
+
+	require.NoError(err)
+	require.Equal(&model.URLInfo{
+		ShortCode:    url.ShortCode,
+		ShortURL:     "localhost:8513/G2ogLe",
+		LongURL:      url.LongURL,
+		OriginalURL:  url.OriginalURL,
+		RedirectCode: http.StatusMovedPermanently,
+	}, info)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_GetURLInfo_Protected() {
+	require := suite.Require()
+	testCases := []struct {
+		name                string
+		ctx                 context.Context
+		expectedDestination bool
+	}{
+		{name: "anonymous", ctx: context.TODO()},
+		{name: "other owner", ctx: WithOwner(context.TODO(), "bob")},
+		{name: "owner", ctx: ownerCtx, expectedDestination: true},
+		{name: "admin", ctx: WithAdmin(WithOwner(context.TODO(), "bob")), expectedDestination: true},
+	}
+
+	for _, tc := range testCases {
+		url := model.URL{ID: 1, LongURL: "http://google.com/", OriginalURL: "http://Google.com", ShortCode: "G2ogLe", OwnerID: testOwner, PasswordHash: "hash"}
+		suite.mockRepo.On("FindByShortCode", tc.ctx, "", url.ShortCode).Return(&url, nil).Once()
+		info, err := suite.service.GetURLInfo(tc.ctx, url.ShortCode)
+
+		require.NoError(err, tc.name)
+		require.True(info.Protected, tc.name)
+		if tc.expectedDestination {
+			require.Equal(url.LongURL, info.LongURL, tc.name)
+			require.Equal(url.OriginalURL, info.OriginalURL, tc.name)
+		} else {
+			require.Empty(info.LongURL, tc.name)
+			require.Empty(info.OriginalURL, tc.name)
+		}
+	}
+}
+
+func (suite *URLServiceTestSuite) TestURLService_GetURLInfo_Failure() {
+	require := suite.Require()
+	suite.mockRepo.On("FindByShortCode", context.TODO(), "", "G2ogLe").Return(nil, gorm.ErrRecordNotFound).Once()
+	info, err := suite.service.GetURLInfo(context.TODO(), "G2ogLe")
+
+	require.ErrorIs(err, ErrURLNotFound)
+	require.Nil(info)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_UpdateLongURL_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		shortCode   string
+		input       string
+		expectedURL string
+	}{
+		{
+			shortCode:   "G2ogLe",
+			input:       "HTTPS://GitHub.com",
+			expectedURL: "https://github.com/",
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.mockRepo.On("FindByShortCode", ownerCtx, "", tc.shortCode).
+			Return(&model.URL{ID: 1, OwnerID: testOwner, LongURL: "http://google.com/", ShortCode: tc.shortCode}, nil).Once()
+		suite.mockRepo.On("UpdateLongURL", ownerCtx, testifyMock.MatchedBy(func(url *model.URL) bool {
+			return url.LongURL == tc.expectedURL && url.OriginalURL == tc.input && url.LongURLHash == model.HashLongURL(tc.expectedURL)
+		})).Return(nil).Once()
+		suite.mockCacheRepo.On("Replace", ownerCtx, testifyMock.MatchedBy(func(url *model.URL) bool {
+			return url.ShortCode == tc.shortCode && url.LongURL == tc.expectedURL
+		})).Return(nil).Once()
+		suite.mockAuditRepo.On("Create", ownerCtx, &model.AuditLog{
+			ShortCode: tc.shortCode,
+			Action:    model.AuditActionUpdate,
+			Actor:     testActor,
+			Details:   "http://google.com/ -> " + tc.expectedURL,
+		}).Return(nil).Once()
+		info, err := suite.service.UpdateLongURL(ownerCtx, tc.shortCode, tc.input, testActor)
+
+		require.NoError(err)
+		require.Equal(tc.expectedURL, info.LongURL)
+		suite.mockCacheRepo.AssertExpectations(suite.T())
+	}
+}
+
+func (suite *URLServiceTestSuite) TestURLService_UpdateLongURL_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		shortCode   string
+		input       string
+		findErr     error
+		updateErr   error
+		expectedErr error
+	}{
+		{
+			shortCode:   "G2ogLe",
+			input:       "javascript:alert(1)",
+			expectedErr: ErrInvalidURL,
+		},
+		{
+			shortCode:   "G2ogLf",
+			input:       "https://github.com",
+			findErr:     gorm.ErrRecordNotFound,
+			expectedErr: ErrURLNotFound,
+		},
+		{
+			shortCode:   "G2ogLg",
+			input:       "https://github.com",
+			updateErr:   gorm.ErrInvalidDB,
+			expectedErr: gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.findErr != nil {
+			suite.mockRepo.On("FindByShortCode", ownerCtx, "", tc.shortCode).Return(nil, tc.findErr).Once()
+		} else {
+			suite.mockRepo.On("FindByShortCode", ownerCtx, "", tc.shortCode).
+				Return(&model.URL{ID: 1, OwnerID: testOwner, ShortCode: tc.shortCode}, nil).Once()
+		}
+
+		if tc.updateErr != nil {
+			suite.mockRepo.On("UpdateLongURL", ownerCtx, testifyMock.Anything).Return(tc.updateErr).Once()
+		}
+
+		info, err := suite.service.UpdateLongURL(ownerCtx, tc.shortCode, tc.input, testActor)
+
+		require.ErrorIs(err, tc.expectedErr)
+		require.Nil(info)
+	}
+
+	suite.mockCacheRepo.AssertNotCalled(suite.T(), "Delete", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything)
+	suite.mockAuditRepo.AssertNotCalled(suite.T(), "Create", testifyMock.Anything, testifyMock.Anything)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_DeleteURL_Success() {
+	require := suite.Require()
+	suite.mockRepo.On("FindByShortCode", ownerCtx, "", "G2ogLe").Return(&model.URL{ID: 1, OwnerID: testOwner, ShortCode: "G2ogLe"}, nil).Once()
+	suite.mockRepo.On("Delete", ownerCtx, "", "G2ogLe").Return(nil).Once()
+	suite.mockCacheRepo.On("Delete", ownerCtx, "", "G2ogLe").Return(errors.New("redis down")).Once()
+	suite.mockAuditRepo.On("Create", ownerCtx, &model.AuditLog{
+		ShortCode: "G2ogLe",
+		Action:    model.AuditActionDelete,
+		Actor:     testActor,
+	}).Return(nil).Once()
+	err := suite.service.DeleteURL(ownerCtx, "G2ogLe", testActor)
+
+	require.NoError(err)
+	suite.mockCacheRepo.AssertExpectations(suite.T())
+	suite.mockAuditRepo.AssertExpectations(suite.T())
+}
+
+func (suite *URLServiceTestSuite) TestURLService_DeleteURL_Failure() {
+	require := suite.Require()
+	suite.mockRepo.On("FindByShortCode", ownerCtx, "", "G2ogLe").Return(&model.URL{ID: 1, OwnerID: testOwner, ShortCode: "G2ogLe"}, nil).Once()
+	suite.mockRepo.On("Delete", ownerCtx, "", "G2ogLe").Return(gorm.ErrRecordNotFound).Once()
+	err := suite.service.DeleteURL(ownerCtx, "G2ogLe", testActor)
+
+	require.ErrorIs(err, ErrURLNotFound)
+	suite.mockCacheRepo.AssertNotCalled(suite.T(), "Delete", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_ManageURL_Ownership() {
+	require := suite.Require()
+	testCases := []struct {
+		name          string
+		ctx           context.Context
+		owner         string
+		expectedFound bool
+	}{
+		{name: "owner", ctx: ownerCtx, owner: testOwner, expectedFound: true},
+		{name: "other owner", ctx: WithOwner(context.TODO(), "bob"), owner: testOwner},
+		{name: "anonymous", ctx: context.TODO(), owner: testOwner},
+		{name: "ownerless link", ctx: ownerCtx},
+		{name: "admin", ctx: WithAdmin(WithOwner(context.TODO(), "bob")), owner: testOwner, expectedFound: true},
+	}
+
+	for _, tc := range testCases {
+		suite.SetupTest()
+		// a copy per call, as disabling the link would make the owner unable to change it afterwards
+		for i := 0; i < 4; i++ {
+			suite.mockRepo.On("FindByShortCode", tc.ctx, "", "G2ogLe").Return(&model.URL{ID: 1, OwnerID: tc.owner, ShortCode: "G2ogLe"}, nil).Once()
+		}
+		suite.mockRepo.On("UpdateLongURL", tc.ctx, testifyMock.Anything).Return(nil)
+		suite.mockRepo.On("UpdateDisabled", tc.ctx, testifyMock.Anything).Return(nil)
+		suite.mockRepo.On("Delete", tc.ctx, "", "G2ogLe").Return(nil)
+		suite.mockCacheRepo.On("Replace", tc.ctx, testifyMock.Anything).Return(nil)
+		suite.mockCacheRepo.On("Delete", tc.ctx, "", "G2ogLe").Return(nil)
+		suite.mockAuditRepo.On("Create", tc.ctx, testifyMock.Anything).Return(nil)
+
+		_, updateErr := suite.service.UpdateLongURL(tc.ctx, "G2ogLe", "https://github.com", testActor)
+		_, disableErr := suite.service.DisableURL(tc.ctx, "G2ogLe", "phishing", testActor)
+		_, enableErr := suite.service.EnableURL(tc.ctx, "G2ogLe", testActor)
+		deleteErr := suite.service.DeleteURL(tc.ctx, "G2ogLe", testActor)
+
+		for _, err := range []error{updateErr, disableErr, enableErr, deleteErr} {
+			if tc.expectedFound {
+				require.NoError(err, tc.name)
+			} else {
+				require.ErrorIs(err, ErrURLNotFound, tc.name)
+			}
+		}
+
+		if !tc.expectedFound {
+			suite.mockRepo.AssertNotCalled(suite.T(), "UpdateLongURL", testifyMock.Anything, testifyMock.Anything)
+			suite.mockRepo.AssertNotCalled(suite.T(), "UpdateDisabled", testifyMock.Anything, testifyMock.Anything)
+			suite.mockRepo.AssertNotCalled(suite.T(), "Delete", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything)
+		}
+	}
+}
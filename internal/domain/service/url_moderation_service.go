@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+)
+
+// DisableURL takes a link down without deleting it: redirects answer 410 with the reason,
+// while the link and its visits are kept as evidence.
+func (svc *Service) DisableURL(ctx context.Context, shortCode string, reason string, actor string) (*model.URLInfo, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrMissingReason
+	}
+
+	url, err := svc.findManagedURL(ctx, shortCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkTakedown(ctx, url); err != nil {
+		return nil, err
+	}
+
+	if url.DisabledAt == nil {
+		now := time.Now()
+		url.DisabledAt = &now
+	}
+
+	url.DisabledReason = reason
+	if err = svc.update(ctx, url, svc.repo.UpdateDisabled); err != nil {
+		return nil, err
+	}
+
+	svc.recordAudit(ctx, shortCode, model.AuditActionDisable, actor, reason)
+	svc.logger.WithFields(logrus.Fields{
+		"shortURL": svc.buildShortURL(url.Domain, shortCode),
+		"reason":   reason,
+	}).Info("Disable short URL")
+
+	return svc.toURLInfo(url), nil
+}
+
+// EnableURL serves a disabled link again; only an admin may lift a takedown.
+func (svc *Service) EnableURL(ctx context.Context, shortCode string, actor string) (*model.URLInfo, error) {
+	url, err := svc.findManagedURL(ctx, shortCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if !url.IsDisabled() {
+		return svc.toURLInfo(url), nil
+	}
+
+	if err = checkTakedown(ctx, url); err != nil {
+		return nil, err
+	}
+
+	url.DisabledAt = nil
+	url.DisabledReason = ""
+	if err = svc.update(ctx, url, svc.repo.UpdateDisabled); err != nil {
+		return nil, err
+	}
+
+	svc.recordAudit(ctx, shortCode, model.AuditActionEnable, actor, "")
+	svc.logger.WithField("shortURL", svc.buildShortURL(url.Domain, shortCode)).Info("Enable short URL")
+
+	return svc.toURLInfo(url), nil
+}
+
+// update stores the changed columns of the URL with write and refreshes its cache entry.
+func (svc *Service) update(ctx context.Context, url *model.URL, write func(ctx context.Context, url *model.URL) error) error {
+	if err := write(ctx, url); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrURLNotFound
+		}
+
+		return err
+	}
+
+	svc.refreshCache(ctx, url)
+
+	return nil
+}
+
+// recordAudit writes an audit entry for a change that has already been applied;
+// a failure is logged rather than returned, as the change cannot be rolled back.
+func (svc *Service) recordAudit(ctx context.Context, shortCode string, action string, actor string, details string) {
+	entry := &model.AuditLog{
+		Domain:    DomainFromContext(ctx),
+		ShortCode: shortCode,
+		Action:    action,
+		Actor:     actor,
+		Details:   details,
+	}
+	if err := svc.auditRepo.Create(ctx, entry); err != nil {
+		svc.logger.WithFields(logrus.Fields{
+			"shortCode": shortCode,
+			"action":    action,
+			"actor":     actor,
+			"details":   details,
+		}).Errorf("failed to record audit entry. Error: %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	testifyMock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+)
+
+func (suite *URLServiceTestSuite) TestURLService_DisableURL_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		shortCode      string
+		reason         string
+		expectedReason string
+	}{
+		{
+			shortCode:      "G2ogLe",
+			reason:         " phishing ",
+			expectedReason: "phishing",
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.mockRepo.On("FindByShortCode", ownerCtx, "", tc.shortCode).
+			Return(&model.URL{ID: 1, OwnerID: testOwner, LongURL: "http://google.com/", ShortCode: tc.shortCode}, nil).Once()
+		suite.mockRepo.On("UpdateDisabled", ownerCtx, testifyMock.MatchedBy(func(url *model.URL) bool {
+			return url.IsDisabled() && url.DisabledReason == tc.expectedReason
+		})).Return(nil).Once()
+		suite.mockCacheRepo.On("Replace", ownerCtx, testifyMock.MatchedBy(func(url *model.URL) bool {
+			return url.IsDisabled() && url.DisabledReason == tc.expectedReason
+		})).Return(nil).Once()
+		suite.mockAuditRepo.On("Create", ownerCtx, &model.AuditLog{
+			ShortCode: tc.shortCode,
+			Action:    model.AuditActionDisable,
+			Actor:     testActor,
+			Details:   tc.expectedReason,
+		}).Return(errors.New("connection refused")).Once()
+		info, err := suite.service.DisableURL(ownerCtx, tc.shortCode, tc.reason, testActor)
+
+		require.NoError(err)
+		require.NotNil(info.DisabledAt)
+		require.Equal(tc.expectedReason, info.DisabledReason)
+		suite.mockCacheRepo.AssertExpectations(suite.T())
+		suite.mockAuditRepo.AssertExpectations(suite.T())
+	}
+}
+
+func (suite *URLServiceTestSuite) TestURLService_DisableURL_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		shortCode   string
+		reason      string
+		findErr     error
+		updateErr   error
+		expectedErr error
+	}{
+		{
+			shortCode:   "G2ogLe",
+			reason:      "  ",
+			expectedErr: ErrMissingReason,
+		},
+		{
+			shortCode:   "G2ogLf",
+			reason:      "phishing",
+			findErr:     gorm.ErrRecordNotFound,
+			expectedErr: ErrURLNotFound,
+		},
+		{
+			shortCode:   "G2ogLg",
+			reason:      "phishing",
+			updateErr:   gorm.ErrRecordNotFound,
+			expectedErr: ErrURLNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.findErr != nil {
+			suite.mockRepo.On("FindByShortCode", ownerCtx, "", tc.shortCode).Return(nil, tc.findErr).Once()
+		} else {
+			suite.mockRepo.On("FindByShortCode", ownerCtx, "", tc.shortCode).
+				Return(&model.URL{ID: 1, OwnerID: testOwner, ShortCode: tc.shortCode}, nil).Once()
+		}
+
+		if tc.updateErr != nil {
+			suite.mockRepo.On("UpdateDisabled", ownerCtx, testifyMock.Anything).Return(tc.updateErr).Once()
+		}
+
+		info, err := suite.service.DisableURL(ownerCtx, tc.shortCode, tc.reason, testActor)
+
+		require.ErrorIs(err, tc.expectedErr)
+		require.Nil(info)
+	}
+
+	suite.mockAuditRepo.AssertNotCalled(suite.T(), "Create", testifyMock.Anything, testifyMock.Anything)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_EnableURL_Success() {
+	require := suite.Require()
+	disabledAt := time.Now().Add(-time.Hour)
+	adminCtx := WithAdmin(WithOwner(context.TODO(), "bob"))
+	suite.mockRepo.On("FindByShortCode", adminCtx, "", "G2ogLe").
+		Return(&model.URL{ID: 1, OwnerID: testOwner, ShortCode: "G2ogLe", DisabledAt: &disabledAt, DisabledReason: "phishing"}, nil).Once()
+	suite.mockRepo.On("UpdateDisabled", adminCtx, testifyMock.MatchedBy(func(url *model.URL) bool {
+		return !url.IsDisabled() && url.DisabledReason == ""
+	})).Return(nil).Once()
+	suite.mockCacheRepo.On("Replace", adminCtx, testifyMock.MatchedBy(func(url *model.URL) bool {
+		return !url.IsDisabled()
+	})).Return(nil).Once()
+	suite.mockAuditRepo.On("Create", adminCtx, &model.AuditLog{
+		ShortCode: "G2ogLe",
+		Action:    model.AuditActionEnable,
+		Actor:     testActor,
+	}).Return(nil).Once()
+	info, err := suite.service.EnableURL(adminCtx, "G2ogLe", testActor)
+
+	require.NoError(err)
+	require.Nil(info.DisabledAt)
+	suite.mockAuditRepo.AssertExpectations(suite.T())
+}
+
+func (suite *URLServiceTestSuite) TestURLService_EnableURL_NotDisabled_Success() {
+	require := suite.Require()
+	suite.mockRepo.On("FindByShortCode", ownerCtx, "", "G2ogLe").
+		Return(&model.URL{ID: 1, OwnerID: testOwner, ShortCode: "G2ogLe"}, nil).Once()
+	info, err := suite.service.EnableURL(ownerCtx, "G2ogLe", testActor)
+
+	require.NoError(err)
+	require.Equal("G2ogLe", info.ShortCode)
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateDisabled", testifyMock.Anything, testifyMock.Anything)
+	suite.mockAuditRepo.AssertNotCalled(suite.T(), "Create", testifyMock.Anything, testifyMock.Anything)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_ManageURL_Disabled_Owner() {
+	require := suite.Require()
+	disabledAt := time.Now().Add(-time.Hour)
+	suite.mockRepo.On("FindByShortCode", ownerCtx, "", "G2ogLe").
+		Return(&model.URL{ID: 1, OwnerID: testOwner, ShortCode: "G2ogLe", DisabledAt: &disabledAt, DisabledReason: "phishing"}, nil)
+
+	_, enableErr := suite.service.EnableURL(ownerCtx, "G2ogLe", testActor)
+	_, updateErr := suite.service.UpdateLongURL(ownerCtx, "G2ogLe", "https://github.com", testActor)
+	deleteErr := suite.service.DeleteURL(ownerCtx, "G2ogLe", testActor)
+	_, disableErr := suite.service.DisableURL(ownerCtx, "G2ogLe", "spam", testActor)
+
+	for _, err := range []error{enableErr, updateErr, deleteErr, disableErr} {
+		require.ErrorIs(err, ErrAdminRequired)
+	}
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateDisabled", testifyMock.Anything, testifyMock.Anything)
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateLongURL", testifyMock.Anything, testifyMock.Anything)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Delete", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything)
+	suite.mockAuditRepo.AssertNotCalled(suite.T(), "Create", testifyMock.Anything, testifyMock.Anything)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_GetLongURL_Disabled_Failure() {
+	require := suite.Require()
+	disabledAt := time.Now().Add(-time.Minute)
+	testCases := []struct {
+		input       string
+		expectedURL model.URL
+		fromCache   bool
+	}{
+		{
+			input: "G2ogLe",
+			expectedURL: model.URL{
+				LongURL:        "http://google.com",
+				ShortCode:      "G2ogLe",
+				DisabledAt:     &disabledAt,
+				DisabledReason: "phishing",
+			},
+			fromCache: true,
+		},
+		{
+			input: "G2ogLf",
+			expectedURL: model.URL{
+				LongURL:        "http://google.com",
+				ShortCode:      "G2ogLf",
+				DisabledAt:     &disabledAt,
+				DisabledReason: "phishing",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.fromCache {
+			suite.mockCacheRepo.On("Get", context.TODO(), "", tc.input).Return(&tc.expectedURL, nil).Once()
+		} else {
+			suite.mockCacheRepo.On("Get", context.TODO(), "", tc.input).Return(nil, redis.Nil).Once()
+			suite.mockRepo.On("FindByShortCode", testifyMock.Anything, "", tc.input).Return(&tc.expectedURL, nil).Once()
+		}
+
+		url, err := suite.service.GetLongURL(context.TODO(), model.RedirectRequest{ShortCode: tc.input})
+
+		require.ErrorIs(err, ErrURLDisabled)
+		var disabledErr *DisabledError
+		require.ErrorAs(err, &disabledErr)
+		require.Equal("phishing", disabledErr.Reason)
+		require.Empty(url)
+	}
+
+	suite.mockCacheRepo.AssertNotCalled(suite.T(), "Set", testifyMock.Anything, testifyMock.Anything)
+}
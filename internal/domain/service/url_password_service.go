@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+)
+
+var (
+	ErrInvalidPassword  = errors.New("invalid password")
+	ErrPasswordRequired = errors.New("password required")
+	ErrWrongPassword    = errors.New("wrong password")
+	ErrTooManyAttempts  = errors.New("too many password attempts")
+)
+
+const (
+	// maxPasswordLength is the bcrypt input limit, longer passwords would be silently truncated
+	maxPasswordLength     = 72
+	defaultMaxAttempts    = 5
+	defaultLockoutTimeout = 15 * time.Minute
+)
+
+// hashPassword returns the bcrypt hash of the password, or an empty hash for a public link.
+func hashPassword(password string) (string, error) {
+	if password == "" {
+		return "", nil
+	}
+
+	if len(password) > maxPasswordLength {
+		return "", ErrInvalidPassword
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// unlock verifies the password of a protected link. A client that makes too many attempts without success
+// is locked out of the link until no attempt was made for the lockout duration. Each attempt is counted
+// before the password is checked, so that concurrent guesses cannot all slip under the limit. The attempts
+// are counted on a best effort basis: when they cannot be the password is still checked, bcrypt is slow enough.
+func (svc *Service) unlock(ctx context.Context, url *model.URL, req model.RedirectRequest) error {
+	if !url.IsProtected() {
+		return nil
+	}
+
+	if req.Password == "" {
+		return ErrPasswordRequired
+	}
+
+	maxAttempts, lockout := svc.lockout()
+	attempts, err := svc.attempts.RecordAttempt(ctx, url.Domain, url.ShortCode, req.ClientIP, lockout)
+	if err != nil {
+		svc.logger.Errorf("failed to record password attempt on '%s'. Error: %v", url.ShortCode, err)
+	}
+
+	if attempts > int64(maxAttempts) {
+		return ErrTooManyAttempts
+	}
+
+	if err = bcrypt.CompareHashAndPassword([]byte(url.PasswordHash), []byte(req.Password)); err != nil {
+		svc.logger.WithFields(logrus.Fields{
+			"shortCode": url.ShortCode,
+			"attempts":  attempts,
+		}).Warn("Wrong password")
+
+		return ErrWrongPassword
+	}
+
+	if err = svc.attempts.Reset(ctx, url.Domain, url.ShortCode, req.ClientIP); err != nil {
+		svc.logger.Errorf("failed to reset password attempts of '%s'. Error: %v", url.ShortCode, err)
+	}
+
+	return nil
+}
+
+func (svc *Service) lockout() (int, time.Duration) {
+	maxAttempts, duration := defaultMaxAttempts, defaultLockoutTimeout
+	if svc.cfg.Shortener.Lockout.MaxAttempts > 0 {
+		maxAttempts = svc.cfg.Shortener.Lockout.MaxAttempts
+	}
+
+	if svc.cfg.Shortener.Lockout.Duration > 0 {
+		duration = svc.cfg.Shortener.Lockout.Duration
+	}
+
+	return maxAttempts, duration
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	testifyMock "github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+)
+
+const testClientIP = "198.51.100.4"
+
+func (suite *URLServiceTestSuite) protectedURL(shortCode string, password string) *model.URL {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	suite.Require().NoError(err)
+
+	return &model.URL{
+		LongURL:      "http://google.com",
+		ShortCode:    shortCode,
+		PasswordHash: string(hash),
+	}
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_Password_Success() {
+	require := suite.Require()
+	suite.mockGen.On("GenerateShortURLCode").Return("gclmd", nil)
+	suite.mockRepo.On("Create", context.TODO(), testifyMock.MatchedBy(func(url *model.URL) bool {
+		return bcrypt.CompareHashAndPassword([]byte(url.PasswordHash), []byte("s3cret")) == nil
+	})).Return(nil)
+	// protected links are never shared, so the existing link is not looked up
+	deduplicate := true
+	url, err := suite.service.CreateShortURL(context.TODO(), model.URLData{URL: "http://google.com", Password: "s3cret", Deduplicate: &deduplicate})
+
+	require.NoError(err)
+	require.Equal("localhost:8513/gclmd", url)
+	suite.mockRepo.AssertNotCalled(suite.T(), "FindByLongURL", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything, testifyMock.Anything)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_Password_Failure() {
+	require := suite.Require()
+	url, err := suite.service.CreateShortURL(context.TODO(), model.URLData{URL: "http://google.com", Password: strings.Repeat("a", 73)})
+
+	require.ErrorIs(err, ErrInvalidPassword)
+	require.Empty(url)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", testifyMock.Anything, testifyMock.Anything)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_GetLongURL_Protected_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		shortCode string
+		attempts  int64
+		err       error
+	}{
+		{shortCode: "prot1", attempts: 1},
+		{shortCode: "prot2", attempts: 5},
+		{shortCode: "prot3", err: errors.New("connection refused")},
+	}
+
+	for _, tc := range testCases {
+		suite.mockCacheRepo.On("Get", context.TODO(), "", tc.shortCode).Return(suite.protectedURL(tc.shortCode, "s3cret"), nil).Once()
+		suite.mockAttempts.On("RecordAttempt", context.TODO(), "", tc.shortCode, testClientIP, 15*time.Minute).Return(tc.attempts, tc.err).Once()
+		suite.mockAttempts.On("Reset", context.TODO(), "", tc.shortCode, testClientIP).Return(nil).Once()
+		url, err := suite.service.GetLongURL(context.TODO(), model.RedirectRequest{
+			ShortCode: tc.shortCode,
+			Password:  "s3cret",
+			ClientIP:  testClientIP,
+		})
+
+		require.NoError(err)
+		require.Equal("http://google.com", url.LongURL)
+	}
+
+	suite.mockAttempts.AssertNumberOfCalls(suite.T(), "Reset", 3)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_GetLongURL_Protected_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		shortCode string
+		password  string
+		attempts  int64
+		expected  error
+	}{
+		{shortCode: "prot1", expected: ErrPasswordRequired},
+		{shortCode: "prot2", password: "guess", attempts: 2, expected: ErrWrongPassword},
+		{shortCode: "prot3", password: "s3cret", attempts: 6, expected: ErrTooManyAttempts},
+	}
+
+	for _, tc := range testCases {
+		suite.mockCacheRepo.On("Get", context.TODO(), "", tc.shortCode).Return(suite.protectedURL(tc.shortCode, "s3cret"), nil).Once()
+		suite.mockAttempts.On("RecordAttempt", context.TODO(), "", tc.shortCode, testClientIP, 15*time.Minute).Return(tc.attempts, nil).Once()
+		url, err := suite.service.GetLongURL(context.TODO(), model.RedirectRequest{
+			ShortCode: tc.shortCode,
+			Password:  tc.password,
+			ClientIP:  testClientIP,
+		})
+
+		require.ErrorIs(err, tc.expected)
+		require.Empty(url)
+	}
+
+	suite.mockAttempts.AssertNumberOfCalls(suite.T(), "RecordAttempt", 2)
+	suite.mockAttempts.AssertNotCalled(suite.T(), "Reset", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything, testifyMock.Anything)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_GetLongURL_Protected_Domain() {
+	require := suite.Require()
+	ctx := WithDomain(context.TODO(), "go.example.com")
+	url := suite.protectedURL("prot1", "s3cret")
+	url.Domain = "go.example.com"
+	suite.mockCacheRepo.On("Get", ctx, "go.example.com", "prot1").Return(url, nil).Once()
+	suite.mockAttempts.On("RecordAttempt", ctx, "go.example.com", "prot1", testClientIP, 15*time.Minute).Return(int64(2), nil).Once()
+
+	_, err := suite.service.GetLongURL(ctx, model.RedirectRequest{ShortCode: "prot1", Password: "guess", ClientIP: testClientIP})
+
+	require.ErrorIs(err, ErrWrongPassword)
+	suite.mockAttempts.AssertExpectations(suite.T())
+}
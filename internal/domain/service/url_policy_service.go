@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/miladbarzideh/shortify/internal/infra"
+	"github.com/miladbarzideh/shortify/pkg/urlpolicy"
+)
+
+// ruleRedirectLoop rejects destinations on one of our own short domains.
+const ruleRedirectLoop = "redirect_loop"
+
+var ErrDestinationBlocked = errors.New("destination blocked")
+
+// HostBlocklist tells whether a host, or one of its parent domains, is blocked.
+type HostBlocklist interface {
+	MatchBlocked(ctx context.Context, host string) (string, bool, error)
+}
+
+// DestinationPolicy decides whether links may point to a long URL.
+type DestinationPolicy interface {
+	Check(ctx context.Context, longURL string) error
+}
+
+// PolicyService rejects destinations that break the rules of the configuration, whose host is on
+// the blocklist, or that point to one of our short domains and would loop.
+type PolicyService struct {
+	logger        *logrus.Logger
+	rules         *urlpolicy.Policy
+	blocklist     HostBlocklist
+	domains       DomainRegistry
+	ownHosts      map[string]struct{}
+	rejectedCount infra.Counter
+}
+
+func NewPolicyService(logger *logrus.Logger,
+	cfg *infra.Config,
+	rules *urlpolicy.Policy,
+	blocklist HostBlocklist,
+	domains DomainRegistry,
+	telemetry *infra.TelemetryProvider,
+) *PolicyService {
+	meter := telemetry.MeterProvider.Meter("policyService")
+	ownHosts := make(map[string]struct{})
+	for _, origin := range []string{cfg.Server.BaseURL, cfg.Server.Address} {
+		if host := originHost(origin); host != "" {
+			ownHosts[host] = struct{}{}
+		}
+	}
+
+	return &PolicyService{
+		logger:        logger,
+		rules:         rules,
+		blocklist:     blocklist,
+		domains:       domains,
+		ownHosts:      ownHosts,
+		rejectedCount: infra.NewCounter(meter, "policy.rejections"),
+	}
+}
+
+// Check returns ErrDestinationBlocked, with the reason, if links must not point to the long URL.
+func (svc *PolicyService) Check(ctx context.Context, longURL string) error {
+	if err := svc.rules.Check(ctx, longURL); err != nil {
+		var violation *urlpolicy.Violation
+		if errors.As(err, &violation) {
+			return svc.reject(ctx, longURL, violation.Rule, violation.Detail)
+		}
+
+		return fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	host := originHost(longURL)
+	matched, blocked, err := svc.blocklist.MatchBlocked(ctx, host)
+	if err != nil {
+		return err
+	}
+
+	if blocked {
+		return svc.reject(ctx, longURL, urlpolicy.RuleBlockedHost, fmt.Sprintf("'%s' is blocked", matched))
+	}
+
+	registered, err := svc.domains.IsRegistered(ctx, host)
+	if err != nil {
+		return err
+	}
+
+	if _, own := svc.ownHosts[host]; own || registered {
+		return svc.reject(ctx, longURL, ruleRedirectLoop, fmt.Sprintf("'%s' is a short link domain", host))
+	}
+
+	return nil
+}
+
+func (svc *PolicyService) reject(ctx context.Context, longURL string, rule string, detail string) error {
+	svc.rejectedCount.Inc(ctx)
+	svc.logger.WithFields(logrus.Fields{
+		"longURL": longURL,
+		"rule":    rule,
+	}).Warn("Reject destination")
+
+	return fmt.Errorf("%w: %s", ErrDestinationBlocked, detail)
+}
+
+// originHost returns the lowercase host of a URL or of a host:port address, without port.
+func originHost(origin string) string {
+	if !strings.Contains(origin, "://") {
+		origin = "//" + origin
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+}
+
+// checkDestination applies the destination policy, when one is configured.
+func (svc *Service) checkDestination(ctx context.Context, longURL string) error {
+	if svc.policy == nil {
+		return nil
+	}
+
+	return svc.policy.Check(ctx, longURL)
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	genMock "github.com/miladbarzideh/shortify/internal/domain/service/mock"
+	"github.com/miladbarzideh/shortify/internal/infra"
+	"github.com/miladbarzideh/shortify/pkg/urlpolicy"
+)
+
+type PolicyServiceTestSuite struct {
+	suite.Suite
+	service       *PolicyService
+	mockBlocklist *genMock.HostBlocklist
+	mockDomains   *genMock.DomainRegistry
+}
+
+func (suite *PolicyServiceTestSuite) SetupTest() {
+	suite.mockBlocklist = new(genMock.HostBlocklist)
+	suite.mockDomains = new(genMock.DomainRegistry)
+	rules, err := urlpolicy.New(urlpolicy.Options{
+		BlockedHosts:    []string{"phish.example"},
+		BlockPrivateIPs: true,
+	})
+	suite.Require().NoError(err)
+	cfg := infra.Config{}
+	cfg.Server.Address = "localhost:8513"
+	cfg.Server.BaseURL = "https://shfy.io"
+	suite.service = NewPolicyService(logrus.New(), &cfg, rules, suite.mockBlocklist, suite.mockDomains, infra.NOOPTelemetry)
+}
+
+func (suite *PolicyServiceTestSuite) TestPolicyService_Check() {
+	require := suite.Require()
+	testCases := []struct {
+		input      string
+		blocked    string
+		registered bool
+		rejected   bool
+	}{
+		{input: "https://google.com/"},
+		{input: "https://phish.example/login", rejected: true},
+		{input: "http://10.0.0.1/", rejected: true},
+		{input: "https://login.evil.com/", blocked: "evil.com", rejected: true},
+		{input: "https://shfy.io/G2ogLe", rejected: true},
+		{input: "https://go.example.com/G2ogLe", registered: true, rejected: true},
+	}
+
+	for _, tc := range testCases {
+		host := originHost(tc.input)
+		suite.mockBlocklist.On("MatchBlocked", context.TODO(), host).Return(tc.blocked, tc.blocked != "", nil).Once()
+		suite.mockDomains.On("IsRegistered", context.TODO(), host).Return(tc.registered, nil).Once()
+
+		err := suite.service.Check(context.TODO(), tc.input)
+
+		if tc.rejected {
+			require.ErrorIs(err, ErrDestinationBlocked, tc.input)
+		} else {
+			require.NoError(err, tc.input)
+		}
+	}
+}
+
+func (suite *PolicyServiceTestSuite) TestPolicyService_Check_Failure() {
+	require := suite.Require()
+	suite.mockBlocklist.On("MatchBlocked", context.TODO(), "google.com").Return("", false, errors.New("connection refused")).Once()
+	err := suite.service.Check(context.TODO(), "https://google.com/")
+
+	require.Error(err)
+	require.NotErrorIs(err, ErrDestinationBlocked)
+}
+
+func TestPolicyServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(PolicyServiceTestSuite))
+}
+
+func (suite *URLServiceTestSuite) TestURLService_DestinationPolicy() {
+	require := suite.Require()
+	mockPolicy := new(genMock.DestinationPolicy)
+	suite.service.policy = mockPolicy
+	suite.service.cfg.Shortener.Policy.CheckOnRedirect = true
+	mockPolicy.On("Check", testifyMock.Anything, "http://evil.com/").Return(ErrDestinationBlocked)
+
+	_, err := suite.service.CreateShortURL(context.TODO(), model.URLData{URL: "http://evil.com"})
+	require.ErrorIs(err, ErrDestinationBlocked)
+
+	results, err := suite.service.CreateShortURLs(context.TODO(), "", []model.URLData{{URL: "http://evil.com"}})
+	require.NoError(err)
+	require.Contains(results[0].Error, ErrDestinationBlocked.Error())
+
+	_, err = suite.service.UpdateLongURL(context.TODO(), "G2ogLe", "http://evil.com", "alice")
+	require.ErrorIs(err, ErrDestinationBlocked)
+
+	suite.mockCacheRepo.On("Get", context.TODO(), "", "G2ogLe").Return(&model.URL{LongURL: "http://evil.com/", ShortCode: "G2ogLe"}, nil).Once()
+	_, err = suite.service.GetLongURL(context.TODO(), model.RedirectRequest{ShortCode: "G2ogLe"})
+	require.ErrorIs(err, ErrDestinationBlocked)
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", testifyMock.Anything, testifyMock.Anything)
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateLongURL", testifyMock.Anything, testifyMock.Anything)
+}
@@ -4,70 +4,243 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
 
 	"github.com/miladbarzideh/shortify/internal/domain/model"
 	"github.com/miladbarzideh/shortify/internal/infra"
+	"github.com/miladbarzideh/shortify/pkg/bloom"
+	"github.com/miladbarzideh/shortify/pkg/urlnorm"
 )
 
 var (
-	ErrURLNotFound        = errors.New("url not found")
-	ErrMaxRetriesExceeded = errors.New("max retries exceeded")
+	ErrURLNotFound         = errors.New("url not found")
+	ErrMaxRetriesExceeded  = errors.New("max retries exceeded")
+	ErrInvalidAlias        = errors.New("invalid alias")
+	ErrAliasTaken          = errors.New("alias already taken")
+	ErrURLExpired          = errors.New("url expired")
+	ErrInvalidExpiry       = errors.New("invalid expiry")
+	ErrInvalidURL          = errors.New("invalid url")
+	ErrURLDisabled         = errors.New("url disabled")
+	ErrMissingReason       = errors.New("reason is required")
+	ErrInvalidRedirectCode = errors.New("invalid redirect code")
+	ErrAdminRequired       = errors.New("disabled links can only be changed by an admin")
 )
 
-const maxRetries = 5
+// DisabledError is returned for a link that has been taken down, along with the reason given.
+type DisabledError struct {
+	Reason string
+}
+
+func (e *DisabledError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrURLDisabled, e.Reason)
+}
+
+func (e *DisabledError) Unwrap() error {
+	return ErrURLDisabled
+}
+
+const (
+	maxRetries     = 5
+	minAliasLength = 3
+	maxAliasLength = 20
+)
+
+var (
+	aliasRegex = regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+	// reservedAliases collide with current or planned routes and must not be used as custom codes.
+	reservedAliases = map[string]struct{}{
+		"api":     {},
+		"admin":   {},
+		"health":  {},
+		"metrics": {},
+		"shorten": {},
+		"static":  {},
+		"stats":   {},
+	}
+)
 
 type URLRepository interface {
 	Create(ctx context.Context, url *model.URL) error
-	FindByShortCode(ctx context.Context, shortCode string) (*model.URL, error)
+	FindByShortCode(ctx context.Context, domain string, shortCode string) (*model.URL, error)
+	FindByLongURL(ctx context.Context, ownerID string, domain string, longURL string) (*model.URL, error)
+	CreateBatch(ctx context.Context, urls []*model.URL) error
+	FindExistingShortCodes(ctx context.Context, domain string, shortCodes []string) ([]string, error)
+	CountByCodeLength(ctx context.Context, length int) (int64, error)
+	FindByLongURLs(ctx context.Context, ownerID string, domain string, longURLs []string) ([]model.URL, error)
+	UpdateLongURL(ctx context.Context, url *model.URL) error
+	UpdateDisabled(ctx context.Context, url *model.URL) error
+	Delete(ctx context.Context, domain string, shortCode string) error
+	List(ctx context.Context, filter model.URLFilter) ([]model.URL, error)
 }
 
+// URLCacheRepository caches links by short code. Set only caches a code that is not cached yet, while Replace
+// overwrites the entry of a changed link, so a lookup that read a link before its change can not cache it again.
+// Get returns model.ErrCachedMissing for a code cached as not existing.
 type URLCacheRepository interface {
 	Set(ctx context.Context, url *model.URL) error
-	Get(ctx context.Context, shortCode string) (*model.URL, error)
+	Replace(ctx context.Context, url *model.URL) error
+	Get(ctx context.Context, domain string, shortCode string) (*model.URL, error)
+	Delete(ctx context.Context, domain string, shortCode string) error
+	SetMissing(ctx context.Context, domain string, shortCode string) error
+	DeleteMissing(ctx context.Context, domain string, shortCodes []string) error
+}
+
+// CodeFilter tells without a lookup that a short code does not exist, like a Bloom filter.
+// It may report codes that do not exist, but never misses an added one.
+type CodeFilter interface {
+	Add(ctx context.Context, items ...string) error
+	MightContain(ctx context.Context, item string) (bool, error)
+}
+
+// DomainRegistry tells whether a host is a registered branded short domain.
+type DomainRegistry interface {
+	IsRegistered(ctx context.Context, host string) (bool, error)
+}
+
+// PasswordAttemptRepository counts the password attempts of a client on a protected link since its last success.
+type PasswordAttemptRepository interface {
+	RecordAttempt(ctx context.Context, domain string, shortCode string, clientIP string, window time.Duration) (int64, error)
+	Reset(ctx context.Context, domain string, shortCode string, clientIP string) error
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, entry *model.AuditLog) error
 }
 
 type Generator interface {
-	GenerateShortURLCode() string
+	GenerateShortURLCode(ctx context.Context) (string, error)
+}
+
+// ResizableGenerator is a Generator whose code length can grow once its keyspace gets crowded.
+type ResizableGenerator interface {
+	Generator
+	Length() int
+	SetLength(length int)
+}
+
+// WorkerPool runs side effects in the background, off the request path.
+type WorkerPool interface {
+	Submit(job func(ctx context.Context)) error
 }
 
 type Service struct {
-	logger     *logrus.Logger
-	cfg        *infra.Config
-	repo       URLRepository
-	cacheRepo  URLCacheRepository
-	gen        Generator
-	cacheStats infra.CacheStats
+	logger    *logrus.Logger
+	cfg       *infra.Config
+	repo      URLRepository
+	cacheRepo URLCacheRepository
+	filter    CodeFilter
+	lock      LookupLock
+	lookups   singleflight.Group
+	auditRepo AuditRepository
+	attempts  PasswordAttemptRepository
+	domains   DomainRegistry
+	policy    DestinationPolicy
+	gen       Generator
+	pool      WorkerPool
+	keyspace  *keyspaceMonitor
+
+	filterRejections     infra.Counter
+	filterFalsePositives infra.Counter
+	lookupsCoalesced     infra.Counter
+	lookupsLockCoalesced infra.Counter
 }
 
 func NewService(logger *logrus.Logger,
 	cfg *infra.Config,
 	repo URLRepository,
 	cacheRepo URLCacheRepository,
+	filter CodeFilter,
+	lock LookupLock,
+	auditRepo AuditRepository,
+	attempts PasswordAttemptRepository,
+	domains DomainRegistry,
+	policy DestinationPolicy,
 	gen Generator,
+	pool WorkerPool,
 	telemetry *infra.TelemetryProvider,
 ) *Service {
 	meter := telemetry.MeterProvider.Meter("urlService")
-	return &Service{
-		logger:     logger,
-		cfg:        cfg,
-		repo:       repo,
-		cacheRepo:  cacheRepo,
-		gen:        gen,
-		cacheStats: infra.NewCacheStats(meter),
+	svc := &Service{
+		logger:    logger,
+		cfg:       cfg,
+		repo:      repo,
+		cacheRepo: cacheRepo,
+		filter:    filter,
+		lock:      lock,
+		auditRepo: auditRepo,
+		attempts:  attempts,
+		domains:   domains,
+		policy:    policy,
+		gen:       gen,
+		pool:      pool,
+
+		filterRejections:     infra.NewCounter(meter, "bloom.rejections"),
+		filterFalsePositives: infra.NewCounter(meter, "bloom.false_positives"),
+		lookupsCoalesced:     infra.NewCounter(meter, "lookup.coalesced"),
+		lookupsLockCoalesced: infra.NewCounter(meter, "lookup.lock_coalesced"),
+	}
+
+	if resizable, ok := gen.(ResizableGenerator); ok && cfg.Shortener.Growth.Enabled {
+		svc.keyspace = newKeyspaceMonitor(logger, cfg.Shortener.Growth, resizable, repo, pool, meter)
 	}
+
+	return svc
 }
 
-func (svc *Service) CreateShortURL(ctx context.Context, longURL string) (string, error) {
-	shortCode := svc.gen.GenerateShortURLCode()
-	url, err := svc.createShortURLWithRetries(ctx, longURL, shortCode)
+// CatchUpCodeLength grows the length of generated codes to fit the links already stored, when growth is enabled.
+// It is meant to run once before serving.
+func (svc *Service) CatchUpCodeLength(ctx context.Context) error {
+	return svc.keyspace.catchUp(ctx)
+}
+
+func (svc *Service) CreateShortURL(ctx context.Context, data model.URLData) (string, error) {
+	owner, _ := OwnerFromContext(ctx)
+	domain, err := svc.resolveDomain(ctx, data.Domain)
+	if err != nil {
+		return "", err
+	}
+
+	url, err := svc.newURL(ctx, data, owner, domain)
+	if err != nil {
+		return "", err
+	}
+
+	if svc.isDeduplicable(data, url) {
+		existing, err := svc.repo.FindByLongURL(ctx, owner, domain, url.LongURL)
+		if err == nil {
+			shortURL := svc.buildShortURL(existing.Domain, existing.ShortCode)
+			svc.logger.WithFields(logrus.Fields{
+				"originalURL": existing.LongURL,
+				"shortURL":    shortURL,
+			}).Debug("Reuse existing short URL")
+			return shortURL, nil
+		}
+
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", err
+		}
+	}
+
+	if data.Alias != "" {
+		err = svc.createShortURLWithAlias(ctx, url, data.Alias)
+	} else {
+		err = svc.createShortURLWithRetries(ctx, url)
+	}
+
 	if err != nil {
 		return "", err
 	}
 
-	shortURL := svc.buildShortURL(url.ShortCode)
+	svc.forgetMissing(ctx, url.Domain, []string{url.ShortCode})
+
+	shortURL := svc.buildShortURL(url.Domain, url.ShortCode)
 	svc.logger.WithFields(logrus.Fields{
 		"originalURL": url.LongURL,
 		"shortURL":    shortURL,
@@ -76,52 +249,536 @@ func (svc *Service) CreateShortURL(ctx context.Context, longURL string) (string,
 	return shortURL, nil
 }
 
-func (svc *Service) createShortURLWithRetries(ctx context.Context, longURL string, shortCode string) (*model.URL, error) {
-	url := &model.URL{ShortCode: shortCode, LongURL: longURL}
+// resolveDomain returns the registered domain a link is created on: the requested one,
+// or else the one the request was made on.
+func (svc *Service) resolveDomain(ctx context.Context, domain string) (string, error) {
+	if domain == "" {
+		return DomainFromContext(ctx), nil
+	}
+
+	domain = NormalizeHost(domain)
+	registered, err := svc.domains.IsRegistered(ctx, domain)
+	if err != nil {
+		return "", err
+	}
+
+	if !registered {
+		return "", fmt.Errorf("%w: '%s'", ErrUnknownDomain, domain)
+	}
+
+	return domain, nil
+}
+
+// newURL validates the request and builds the URL of the owner on the domain to store, without its short code.
+func (svc *Service) newURL(ctx context.Context, data model.URLData, ownerID string, domain string) (*model.URL, error) {
+	longURL, err := svc.normalize(data.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = svc.checkDestination(ctx, longURL); err != nil {
+		return nil, err
+	}
+
+	expiresAt, err := resolveExpiry(data, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Alias != "" {
+		if err = ValidateAlias(data.Alias); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = validateRedirectCode(data.RedirectCode); err != nil {
+		return nil, err
+	}
+
+	passwordHash, err := hashPassword(data.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.URL{
+		LongURL:      longURL,
+		LongURLHash:  model.HashLongURL(longURL),
+		OriginalURL:  data.URL,
+		Domain:       domain,
+		OwnerID:      ownerID,
+		ExpiresAt:    expiresAt,
+		PasswordHash: passwordHash,
+		RedirectCode: data.RedirectCode,
+	}, nil
+}
+
+func (svc *Service) normalize(longURL string) (string, error) {
+	canonicalURL, err := urlnorm.Normalize(longURL, urlnorm.Options{
+		StripFragment: svc.cfg.Shortener.Normalization.StripFragment,
+		SortQuery:     svc.cfg.Shortener.Normalization.SortQuery,
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	return canonicalURL, nil
+}
+
+// isDeduplicable reports whether an existing code may be returned for the request.
+// Only permanent, public, generated codes of the same owner are shared: an alias, an expiry, a password
+// or a redirect code is specific to the caller.
+func (svc *Service) isDeduplicable(data model.URLData, url *model.URL) bool {
+	return data.Alias == "" && url.ExpiresAt == nil && !url.IsProtected() && url.RedirectCode == 0 &&
+		svc.shouldDeduplicate(data)
+}
+
+func (svc *Service) createShortURLWithAlias(ctx context.Context, url *model.URL, alias string) error {
+	url.ShortCode = alias
+	if err := svc.addToFilter(ctx, url.Domain, alias); err != nil {
+		return err
+	}
+
+	if err := svc.repo.Create(ctx, url); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return fmt.Errorf("%w: '%s'", ErrAliasTaken, alias)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// createShortURLWithRetries stores the URL with a generated code, and with a new one when the code is taken.
+func (svc *Service) createShortURLWithRetries(ctx context.Context, url *model.URL) error {
 	for i := 0; i < maxRetries; i++ {
-		err := svc.repo.Create(ctx, url)
+		shortCode, err := svc.gen.GenerateShortURLCode(ctx)
+		if err != nil {
+			return err
+		}
+
+		url.ShortCode = shortCode
+		if err = svc.addToFilter(ctx, url.Domain, shortCode); err != nil {
+			return err
+		}
+
+		err = svc.repo.Create(ctx, url)
 		if err == nil {
-			return url, nil
+			svc.keyspace.record(1, 0)
+			return nil
 		}
 
 		if !errors.Is(err, gorm.ErrDuplicatedKey) {
+			return err
+		}
+
+		svc.keyspace.record(1, 1)
+
+		svc.logger.Debugf("short code '%s' of URL '%s' is taken. Retrying...", shortCode, url.LongURL)
+	}
+
+	return fmt.Errorf("failed to create short URL after %d retries %w", maxRetries, ErrMaxRetriesExceeded)
+}
+
+func (svc *Service) GetLongURL(ctx context.Context, req model.RedirectRequest) (*model.Redirect, error) {
+	url, err := svc.findServableURL(ctx, req.ShortCode)
+	if err != nil {
+		return nil, err
+	}
+
+	// destinations are checked again in case a host was blocked after the link was created
+	if svc.cfg.Shortener.Policy.CheckOnRedirect {
+		if err = svc.checkDestination(ctx, url.LongURL); err != nil {
 			return nil, err
 		}
+	}
+
+	if err = svc.unlock(ctx, url, req); err != nil {
+		return nil, err
+	}
+
+	return &model.Redirect{
+		LongURL:    url.LongURL,
+		StatusCode: svc.redirectCode(url),
+	}, nil
+}
+
+// redirectCode returns the status the URL redirects with: its own, or else the configured default,
+// or else 301 which was the only status before it became configurable.
+func (svc *Service) redirectCode(url *model.URL) int {
+	if url.RedirectCode != 0 {
+		return url.RedirectCode
+	}
 
-		svc.logger.Debugf("failed to create short URL '%s'. Retrying...", longURL)
+	if validateRedirectCode(svc.cfg.Shortener.RedirectCode) == nil && svc.cfg.Shortener.RedirectCode != 0 {
+		return svc.cfg.Shortener.RedirectCode
 	}
 
-	return nil, fmt.Errorf("failed to create short URL after %d retries %w", maxRetries, ErrMaxRetriesExceeded)
+	return http.StatusMovedPermanently
 }
 
-func (svc *Service) GetLongURL(ctx context.Context, shortCode string) (string, error) {
-	if url, err := svc.cacheRepo.Get(ctx, shortCode); err == nil {
-		svc.cacheStats.Hits.Inc(ctx)
-		return url.LongURL, nil
+// findServableURL reads the URL from the cache, or else from the database, and checks it can be redirected to.
+func (svc *Service) findServableURL(ctx context.Context, shortCode string) (*model.URL, error) {
+	domain := DomainFromContext(ctx)
+	exists, filtered := svc.mightExist(ctx, domain, shortCode)
+	if !exists {
+		return nil, ErrURLNotFound
 	}
 
-	url, err := svc.repo.FindByShortCode(ctx, shortCode)
+	if url, err := svc.cacheRepo.Get(ctx, domain, shortCode); err == nil {
+		if err = checkServable(url, time.Now()); err != nil {
+			return nil, err
+		}
+
+		return url, nil
+	} else if errors.Is(err, model.ErrCachedMissing) {
+		return nil, ErrURLNotFound
+	}
+
+	url, err := svc.lookupURL(ctx, domain, shortCode)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", ErrURLNotFound
+		if errors.Is(err, ErrURLNotFound) && filtered {
+			svc.filterFalsePositives.Inc(ctx)
 		}
 
-		return "", err
+		return nil, err
 	}
 
-	if err = svc.cacheRepo.Set(ctx, url); err != nil {
-		svc.logger.Errorf("failed to cache short URL '%s'. Error: %v", shortCode, err)
+	if err = checkServable(url, time.Now()); err != nil {
+		return nil, err
 	}
 
 	svc.logger.WithFields(logrus.Fields{
 		"originalURL": url.LongURL,
-		"shortURL":    svc.buildShortURL(shortCode),
+		"shortURL":    svc.buildShortURL(domain, shortCode),
 	}).Debug("read URL from database")
-	svc.cacheStats.Hits.Inc(ctx)
 
-	return url.LongURL, nil
+	return url, nil
 }
 
-func (svc *Service) buildShortURL(shortCode string) string {
-	return fmt.Sprintf("%s/api/v1/urls/%s", svc.cfg.Server.Address, shortCode)
+// mightExist asks the filter whether the code may exist, and reports whether the filter could tell.
+// The code may exist when the filter is disabled, not built yet or unavailable.
+func (svc *Service) mightExist(ctx context.Context, domain string, shortCode string) (bool, bool) {
+	if svc.filter == nil {
+		return true, false
+	}
+
+	exists, err := svc.filter.MightContain(ctx, filterItem(domain, shortCode))
+	if err != nil {
+		if !errors.Is(err, bloom.ErrNotBuilt) {
+			svc.logger.Errorf("failed to check short code '%s' in filter. Error: %v", shortCode, err)
+		}
+
+		return true, false
+	}
+
+	if !exists {
+		svc.filterRejections.Inc(ctx)
+	}
+
+	return exists, true
+}
+
+// addToFilter adds the codes to the filter before they are stored, so the filter never rejects a stored code.
+func (svc *Service) addToFilter(ctx context.Context, domain string, shortCodes ...string) error {
+	if svc.filter == nil {
+		return nil
+	}
+
+	items := make([]string, len(shortCodes))
+	for i, shortCode := range shortCodes {
+		items[i] = filterItem(domain, shortCode)
+	}
+
+	return svc.filter.Add(ctx, items...)
+}
+
+// filterItem identifies the code of a domain in the filter, the codes of the default domain standing alone.
+func filterItem(domain string, shortCode string) string {
+	if domain == "" {
+		return shortCode
+	}
+
+	return domain + "/" + shortCode
+}
+
+// checkServable reports why the URL must not be redirected to, if it must not.
+func checkServable(url *model.URL, now time.Time) error {
+	if url.IsDisabled() {
+		return &DisabledError{Reason: url.DisabledReason}
+	}
+
+	if url.IsExpired(now) {
+		return ErrURLExpired
+	}
+
+	return nil
+}
+
+func (svc *Service) shouldDeduplicate(data model.URLData) bool {
+	if data.Deduplicate != nil {
+		return *data.Deduplicate
+	}
+
+	return svc.cfg.Shortener.Deduplicate
+}
+
+// resolveExpiry turns the relative or absolute expiry of the request into an absolute timestamp.
+func resolveExpiry(data model.URLData, now time.Time) (*time.Time, error) {
+	switch {
+	case data.ExpiresIn != 0 && data.ExpiresAt != nil:
+		return nil, fmt.Errorf("%w: expires_in and expires_at are mutually exclusive", ErrInvalidExpiry)
+	case data.ExpiresIn < 0:
+		return nil, fmt.Errorf("%w: expires_in must be positive", ErrInvalidExpiry)
+	case data.ExpiresIn > 0:
+		expiresAt := now.Add(time.Duration(data.ExpiresIn) * time.Second)
+		return &expiresAt, nil
+	case data.ExpiresAt != nil && !data.ExpiresAt.After(now):
+		return nil, fmt.Errorf("%w: expires_at must be in the future", ErrInvalidExpiry)
+	default:
+		return data.ExpiresAt, nil
+	}
+}
+
+// validateRedirectCode accepts the redirect statuses a link may use, or 0 for the default.
+func validateRedirectCode(code int) error {
+	switch code {
+	case 0, http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %d, must be 301, 302, 307 or 308", ErrInvalidRedirectCode, code)
+}
+
+// ValidateAlias checks the charset, length bounds and reserved words of a custom alias.
+func ValidateAlias(alias string) error {
+	if len(alias) < minAliasLength || len(alias) > maxAliasLength {
+		return fmt.Errorf("%w: length must be between %d and %d characters", ErrInvalidAlias, minAliasLength, maxAliasLength)
+	}
+
+	if !aliasRegex.MatchString(alias) {
+		return fmt.Errorf("%w: only letters, digits, '-' and '_' are allowed", ErrInvalidAlias)
+	}
+
+	if _, ok := reservedAliases[strings.ToLower(alias)]; ok {
+		return fmt.Errorf("%w: '%s' is reserved", ErrInvalidAlias, alias)
+	}
+
+	return nil
+}
+
+// IsValidShortCode reports whether s is made of characters allowed in generated codes or aliases.
+func IsValidShortCode(s string) bool {
+	return aliasRegex.MatchString(s)
+}
+
+// GetURLInfo returns the details of a link. The info endpoint is public, so the destination of a protected link
+// is only disclosed to its owner or an admin.
+func (svc *Service) GetURLInfo(ctx context.Context, shortCode string) (*model.URLInfo, error) {
+	url, err := svc.findByShortCode(ctx, shortCode)
+	if err != nil {
+		return nil, err
+	}
+
+	info := svc.toURLInfo(url)
+	if url.IsProtected() && !canManage(ctx, url) {
+		info.LongURL = ""
+		info.OriginalURL = ""
+	}
+
+	return info, nil
+}
+
+// UpdateLongURL changes the destination of a short URL and refreshes its cache entry.
+func (svc *Service) UpdateLongURL(ctx context.Context, shortCode string, longURL string, actor string) (*model.URLInfo, error) {
+	canonicalURL, err := svc.normalize(longURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = svc.checkDestination(ctx, canonicalURL); err != nil {
+		return nil, err
+	}
+
+	url, err := svc.findManagedURL(ctx, shortCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkTakedown(ctx, url); err != nil {
+		return nil, err
+	}
+
+	previousURL := url.LongURL
+	url.LongURL = canonicalURL
+	url.LongURLHash = model.HashLongURL(canonicalURL)
+	url.OriginalURL = longURL
+	if err = svc.update(ctx, url, svc.repo.UpdateLongURL); err != nil {
+		return nil, err
+	}
+
+	svc.recordAudit(ctx, shortCode, model.AuditActionUpdate, actor, fmt.Sprintf("%s -> %s", previousURL, canonicalURL))
+	svc.logger.WithFields(logrus.Fields{
+		"originalURL": url.LongURL,
+		"shortURL":    svc.buildShortURL(url.Domain, shortCode),
+	}).Debug("Update short URL")
+
+	return svc.toURLInfo(url), nil
+}
+
+// DeleteURL soft deletes a short URL; its code stays reserved and its history is kept.
+func (svc *Service) DeleteURL(ctx context.Context, shortCode string, actor string) error {
+	url, err := svc.findManagedURL(ctx, shortCode)
+	if err != nil {
+		return err
+	}
+
+	if err = checkTakedown(ctx, url); err != nil {
+		return err
+	}
+
+	domain := DomainFromContext(ctx)
+	if err = svc.repo.Delete(ctx, domain, shortCode); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrURLNotFound
+		}
+
+		return err
+	}
+
+	svc.invalidateCache(ctx, domain, shortCode)
+	svc.recordAudit(ctx, shortCode, model.AuditActionDelete, actor, "")
+	svc.logger.WithField("shortURL", svc.buildShortURL(domain, shortCode)).Debug("Delete short URL")
+
+	return nil
+}
+
+// findByShortCode returns the URL with the short code on the domain of the request.
+func (svc *Service) findByShortCode(ctx context.Context, shortCode string) (*model.URL, error) {
+	url, err := svc.repo.FindByShortCode(ctx, DomainFromContext(ctx), shortCode)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrURLNotFound
+		}
+
+		return nil, err
+	}
+
+	return url, nil
+}
+
+// findManagedURL returns the URL with the short code when the caller may change it: its owner, or an admin.
+// The links of other owners are reported as not found, so that their codes are not disclosed.
+func (svc *Service) findManagedURL(ctx context.Context, shortCode string) (*model.URL, error) {
+	url, err := svc.findByShortCode(ctx, shortCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canManage(ctx, url) {
+		return nil, ErrURLNotFound
+	}
+
+	return url, nil
+}
+
+// checkTakedown rejects changes of a disabled link by its owner: the link may have been taken down for abuse,
+// so only an admin may enable it again, change its destination or delete it.
+func checkTakedown(ctx context.Context, url *model.URL) error {
+	if url.IsDisabled() && !IsAdmin(ctx) {
+		return ErrAdminRequired
+	}
+
+	return nil
+}
+
+// canManage reports whether the caller owns the URL or authenticated with an admin key.
+func canManage(ctx context.Context, url *model.URL) bool {
+	if IsAdmin(ctx) {
+		return true
+	}
+
+	owner, ok := OwnerFromContext(ctx)
+
+	return ok && owner == url.OwnerID
+}
+
+// refreshCache caches the changed URL over its previous version, so redirects pick up the change immediately.
+func (svc *Service) refreshCache(ctx context.Context, url *model.URL) {
+	if err := svc.cacheRepo.Replace(ctx, url); err != nil {
+		svc.logger.Errorf("failed to refresh cached short URL '%s'. Error: %v", url.ShortCode, err)
+	}
+}
+
+func (svc *Service) invalidateCache(ctx context.Context, domain string, shortCode string) {
+	if err := svc.cacheRepo.Delete(ctx, domain, shortCode); err != nil {
+		svc.logger.Errorf("failed to invalidate cached short URL '%s'. Error: %v", shortCode, err)
+	}
+}
+
+func (svc *Service) toURLInfo(url *model.URL) *model.URLInfo {
+	return &model.URLInfo{
+		Domain:       url.Domain,
+		ShortCode:    url.ShortCode,
+		ShortURL:     svc.buildShortURL(url.Domain, url.ShortCode),
+		LongURL:      url.LongURL,
+		OriginalURL:  url.OriginalURL,
+		OwnerID:      url.OwnerID,
+		ExpiresAt:    url.ExpiresAt,
+		Protected:    url.IsProtected(),
+		RedirectCode: svc.redirectCode(url),
+		CreatedAt:    url.CreatedAt,
+		UpdatedAt:    url.UpdatedAt,
+
+		DisabledAt:     url.DisabledAt,
+		DisabledReason: url.DisabledReason,
+	}
+}
+
+// warmUpCache writes the URL to the cache in the background; when the pool is saturated
+// the warm-up is skipped and the next request reads from the database again.
+func (svc *Service) warmUpCache(url *model.URL) {
+	err := svc.pool.Submit(func(ctx context.Context) {
+		if err := svc.cacheRepo.Set(ctx, url); err != nil {
+			svc.logger.Errorf("failed to cache short URL '%s'. Error: %v", url.ShortCode, err)
+		}
+	})
+	if err != nil {
+		svc.logger.Warnf("skip caching short URL '%s'. Error: %v", url.ShortCode, err)
+	}
+}
+
+// cacheMissing caches in the background that the code does not exist, so scans of random codes
+// do not reach the database on every request.
+func (svc *Service) cacheMissing(domain string, shortCode string) {
+	err := svc.pool.Submit(func(ctx context.Context) {
+		if err := svc.cacheRepo.SetMissing(ctx, domain, shortCode); err != nil {
+			svc.logger.Errorf("failed to cache missing short URL '%s'. Error: %v", shortCode, err)
+		}
+	})
+	if err != nil {
+		svc.logger.Warnf("skip caching missing short URL '%s'. Error: %v", shortCode, err)
+	}
+}
+
+// forgetMissing evicts the created codes that were cached as missing, so they are served right away.
+func (svc *Service) forgetMissing(ctx context.Context, domain string, shortCodes []string) {
+	if err := svc.cacheRepo.DeleteMissing(ctx, domain, shortCodes); err != nil {
+		svc.logger.Errorf("failed to evict missing short URLs %v from cache. Error: %v", shortCodes, err)
+	}
+}
+
+// buildShortURL returns the compact link served by the root redirect route: on its branded domain,
+// or else on the public base URL when configured.
+func (svc *Service) buildShortURL(domain string, shortCode string) string {
+	if domain != "" {
+		return fmt.Sprintf("https://%s/%s", domain, shortCode)
+	}
+
+	base := svc.cfg.Server.BaseURL
+	if base == "" {
+		base = svc.cfg.Server.Address
+	}
+
+	return fmt.Sprintf("%s/%s", strings.TrimRight(base, "/"), shortCode)
 }
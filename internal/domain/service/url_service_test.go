@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
@@ -13,6 +16,7 @@ import (
 	"github.com/miladbarzideh/shortify/internal/domain/model"
 	genMock "github.com/miladbarzideh/shortify/internal/domain/service/mock"
 	"github.com/miladbarzideh/shortify/internal/infra"
+	"github.com/miladbarzideh/shortify/pkg/workerpool"
 )
 
 type URLServiceTestSuite struct {
@@ -20,17 +24,27 @@ type URLServiceTestSuite struct {
 	service       *Service
 	mockRepo      *genMock.Repository
 	mockCacheRepo *genMock.CacheRepository
+	mockAuditRepo *genMock.AuditRepository
+	mockAttempts  *genMock.PasswordAttemptRepository
+	mockDomains   *genMock.DomainRegistry
 	mockGen       *genMock.Generator
+	mockPool      *genMock.WorkerPool
 }
 
 func (suite *URLServiceTestSuite) SetupTest() {
 	suite.mockRepo = new(genMock.Repository)
 	suite.mockCacheRepo = new(genMock.CacheRepository)
+	suite.mockAuditRepo = new(genMock.AuditRepository)
+	suite.mockAttempts = new(genMock.PasswordAttemptRepository)
+	suite.mockDomains = new(genMock.DomainRegistry)
 	suite.mockGen = new(genMock.Generator)
+	suite.mockPool = new(genMock.WorkerPool)
+	suite.mockPool.On("Submit").Return(nil)
+	suite.mockCacheRepo.On("DeleteMissing", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything).Return(nil).Maybe()
 	cfg := infra.Config{}
 	cfg.Server.Address = "localhost:8513"
 	cfg.Shortener.CodeLength = 7
-	suite.service = NewService(logrus.New(), &cfg, suite.mockRepo, suite.mockCacheRepo, suite.mockGen, infra.NOOPTelemetry)
+	suite.service = NewService(logrus.New(), &cfg, suite.mockRepo, suite.mockCacheRepo, nil, nil, suite.mockAuditRepo, suite.mockAttempts, suite.mockDomains, nil, suite.mockGen, suite.mockPool, infra.NOOPTelemetry)
 }
 
 func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_Success() {
@@ -49,9 +63,9 @@ func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_Success() {
 	}
 
 	for _, tc := range testCases {
-		suite.mockGen.On("GenerateShortURLCode").Return(tc.expectedURL.ShortCode)
+		suite.mockGen.On("GenerateShortURLCode").Return(tc.expectedURL.ShortCode, nil)
 		suite.mockRepo.On("Create", context.TODO(), testifyMock.Anything).Return(nil)
-		url, err := suite.service.CreateShortURL(context.TODO(), tc.input)
+		url, err := suite.service.CreateShortURL(context.TODO(), model.URLData{URL: tc.input})
 
 		require.NoError(err)
 		require.NotEmpty(url)
@@ -74,9 +88,9 @@ func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_Failure() {
 	}
 
 	for _, tc := range testCases {
-		suite.mockGen.On("GenerateShortURLCode").Return(tc.expectedURL.ShortCode)
+		suite.mockGen.On("GenerateShortURLCode").Return(tc.expectedURL.ShortCode, nil)
 		suite.mockRepo.On("Create", context.TODO(), testifyMock.Anything).Return(gorm.ErrInvalidData)
-		url, err := suite.service.CreateShortURL(context.TODO(), tc.input)
+		url, err := suite.service.CreateShortURL(context.TODO(), model.URLData{URL: tc.input})
 
 		require.Error(err)
 		require.Empty(url)
@@ -97,16 +111,74 @@ func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_DoRetry_Success(
 	}
 
 	for _, tc := range testCases {
-		suite.mockGen.On("GenerateShortURLCode").Return(tc.input.ShortCode)
+		suite.mockGen.On("GenerateShortURLCode").Return(tc.input.ShortCode, nil)
 		suite.mockRepo.On("Create", context.TODO(), testifyMock.Anything).Return(gorm.ErrDuplicatedKey).Once()
 		suite.mockRepo.On("Create", context.TODO(), testifyMock.Anything).Return(nil).Once()
-		url, err := suite.service.CreateShortURL(context.TODO(), tc.input.LongURL)
+		url, err := suite.service.CreateShortURL(context.TODO(), model.URLData{URL: tc.input.LongURL})
 
 		require.NoError(err)
 		require.NotEmpty(url)
 	}
 }
 
+func (suite *URLServiceTestSuite) TestURLService_BuildShortURL_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		baseURL  string
+		domain   string
+		expected string
+	}{
+		{baseURL: "", expected: "localhost:8513/gclmd"},
+		{baseURL: "https://shfy.io", expected: "https://shfy.io/gclmd"},
+		{baseURL: "https://shfy.io/", expected: "https://shfy.io/gclmd"},
+		{baseURL: "https://shfy.io", domain: "go.example.com", expected: "https://go.example.com/gclmd"},
+	}
+
+	for _, tc := range testCases {
+		suite.service.cfg.Server.BaseURL = tc.baseURL
+
+		require.Equal(tc.expected, suite.service.buildShortURL(tc.domain, "gclmd"))
+	}
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_RetryWithNewCode_Success() {
+	require := suite.Require()
+	suite.mockGen.On("GenerateShortURLCode").Return("aaaaa", nil).Once()
+	suite.mockGen.On("GenerateShortURLCode").Return("bbbbb", nil).Once()
+	suite.mockRepo.On("Create", context.TODO(), testifyMock.MatchedBy(func(url *model.URL) bool {
+		return url.ShortCode == "aaaaa"
+	})).Return(gorm.ErrDuplicatedKey).Once()
+	suite.mockRepo.On("Create", context.TODO(), testifyMock.MatchedBy(func(url *model.URL) bool {
+		return url.ShortCode == "bbbbb"
+	})).Return(nil).Once()
+	url, err := suite.service.CreateShortURL(context.TODO(), model.URLData{URL: "http://google.com"})
+
+	require.NoError(err)
+	require.Equal("localhost:8513/bbbbb", url)
+	suite.mockGen.AssertNumberOfCalls(suite.T(), "GenerateShortURLCode", 2)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_MaxRetries_Failure() {
+	require := suite.Require()
+	suite.mockGen.On("GenerateShortURLCode").Return("aaaaa", nil)
+	suite.mockRepo.On("Create", context.TODO(), testifyMock.Anything).Return(gorm.ErrDuplicatedKey)
+	url, err := suite.service.CreateShortURL(context.TODO(), model.URLData{URL: "http://google.com"})
+
+	require.ErrorIs(err, ErrMaxRetriesExceeded)
+	require.Empty(url)
+	suite.mockGen.AssertNumberOfCalls(suite.T(), "GenerateShortURLCode", maxRetries)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_GeneratorError_Failure() {
+	require := suite.Require()
+	suite.mockGen.On("GenerateShortURLCode").Return("", errors.New("sequence unavailable")).Once()
+	url, err := suite.service.CreateShortURL(context.TODO(), model.URLData{URL: "http://google.com"})
+
+	require.Error(err)
+	require.Empty(url)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", testifyMock.Anything, testifyMock.Anything)
+}
+
 func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_DoRetry_Failure() {
 	require := suite.Require()
 	testCases := []struct {
@@ -121,16 +193,261 @@ func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_DoRetry_Failure(
 	}
 
 	for _, tc := range testCases {
-		suite.mockGen.On("GenerateShortURLCode").Return(tc.input.ShortCode)
+		suite.mockGen.On("GenerateShortURLCode").Return(tc.input.ShortCode, nil)
 		suite.mockRepo.On("Create", context.TODO(), testifyMock.Anything).Return(gorm.ErrDuplicatedKey).Once()
 		suite.mockRepo.On("Create", context.TODO(), testifyMock.Anything).Return(gorm.ErrInvalidData).Once()
-		url, err := suite.service.CreateShortURL(context.TODO(), tc.input.LongURL)
+		url, err := suite.service.CreateShortURL(context.TODO(), model.URLData{URL: tc.input.LongURL})
 
 		require.Error(err)
 		require.Empty(url)
 	}
 }
 
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_WithAlias_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		input       model.URLData
+		expectedURL string
+	}{
+		{
+			input:       model.URLData{URL: "http://google.com", Alias: "spring-sale"},
+			expectedURL: "localhost:8513/spring-sale",
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.mockRepo.On("Create", context.TODO(), testifyMock.Anything).Return(nil).Once()
+		url, err := suite.service.CreateShortURL(context.TODO(), tc.input)
+
+		require.NoError(err)
+		require.Equal(tc.expectedURL, url)
+		suite.mockGen.AssertNotCalled(suite.T(), "GenerateShortURLCode")
+	}
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_WithAlias_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		input       model.URLData
+		repoErr     error
+		expectedErr error
+	}{
+		{
+			input:       model.URLData{URL: "http://google.com", Alias: "ab"},
+			expectedErr: ErrInvalidAlias,
+		},
+		{
+			input:       model.URLData{URL: "http://google.com", Alias: "spring sale!"},
+			expectedErr: ErrInvalidAlias,
+		},
+		{
+			input:       model.URLData{URL: "http://google.com", Alias: "API"},
+			expectedErr: ErrInvalidAlias,
+		},
+		{
+			input:       model.URLData{URL: "http://google.com", Alias: "spring-sale"},
+			repoErr:     gorm.ErrDuplicatedKey,
+			expectedErr: ErrAliasTaken,
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.repoErr != nil {
+			suite.mockRepo.On("Create", context.TODO(), testifyMock.Anything).Return(tc.repoErr).Once()
+		}
+
+		url, err := suite.service.CreateShortURL(context.TODO(), tc.input)
+
+		require.ErrorIs(err, tc.expectedErr)
+		require.Empty(url)
+	}
+
+	suite.mockRepo.AssertNumberOfCalls(suite.T(), "Create", 1)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_WithExpiry_Success() {
+	require := suite.Require()
+	expiresAt := time.Now().Add(time.Hour)
+	testCases := []struct {
+		input model.URLData
+	}{
+		{
+			input: model.URLData{URL: "http://google.com", ExpiresIn: 3600},
+		},
+		{
+			input: model.URLData{URL: "http://google.com", ExpiresAt: &expiresAt},
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.mockGen.On("GenerateShortURLCode").Return("gclmd", nil).Once()
+		suite.mockRepo.On("Create", context.TODO(), testifyMock.MatchedBy(func(url *model.URL) bool {
+			return url.ExpiresAt != nil && url.ExpiresAt.After(time.Now())
+		})).Return(nil).Once()
+		url, err := suite.service.CreateShortURL(context.TODO(), tc.input)
+
+		require.NoError(err)
+		require.NotEmpty(url)
+	}
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_WithExpiry_Failure() {
+	require := suite.Require()
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	testCases := []struct {
+		input model.URLData
+	}{
+		{
+			input: model.URLData{URL: "http://google.com", ExpiresIn: -10},
+		},
+		{
+			input: model.URLData{URL: "http://google.com", ExpiresAt: &past},
+		},
+		{
+			input: model.URLData{URL: "http://google.com", ExpiresIn: 60, ExpiresAt: &future},
+		},
+	}
+
+	for _, tc := range testCases {
+		url, err := suite.service.CreateShortURL(context.TODO(), tc.input)
+
+		require.ErrorIs(err, ErrInvalidExpiry)
+		require.Empty(url)
+	}
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", testifyMock.Anything, testifyMock.Anything)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_Normalize_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		input       model.URLData
+		expectedURL model.URL
+	}{
+		{
+			input: model.URLData{URL: "HTTPS://Bücher.Example:443/katalog?b=2&a=1#top"},
+			expectedURL: model.URL{
+				LongURL:     "https://xn--bcher-kva.example/katalog?a=1&b=2",
+				OriginalURL: "HTTPS://Bücher.Example:443/katalog?b=2&a=1#top",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.service.cfg.Shortener.Normalization.StripFragment = true
+		suite.service.cfg.Shortener.Normalization.SortQuery = true
+		suite.mockGen.On("GenerateShortURLCode").Return("gclmd", nil).Once()
+		suite.mockRepo.On("Create", context.TODO(), testifyMock.MatchedBy(func(url *model.URL) bool {
+			return url.LongURL == tc.expectedURL.LongURL &&
+				url.OriginalURL == tc.expectedURL.OriginalURL &&
+				url.LongURLHash == model.HashLongURL(tc.expectedURL.LongURL)
+		})).Return(nil).Once()
+		url, err := suite.service.CreateShortURL(context.TODO(), tc.input)
+
+		require.NoError(err)
+		require.NotEmpty(url)
+	}
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_Normalize_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		input model.URLData
+	}{
+		{input: model.URLData{URL: "javascript:alert(1)"}},
+		{input: model.URLData{URL: "file:///etc/passwd"}},
+	}
+
+	for _, tc := range testCases {
+		url, err := suite.service.CreateShortURL(context.TODO(), tc.input)
+
+		require.ErrorIs(err, ErrInvalidURL)
+		require.Empty(url)
+	}
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", testifyMock.Anything, testifyMock.Anything)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_Deduplicate_Success() {
+	require := suite.Require()
+	enabled, disabled := true, false
+	canonicalURL := "http://google.com/"
+	ctx := WithOwner(context.TODO(), "alice")
+	existing := model.URL{LongURL: canonicalURL, ShortCode: "gclmd"}
+	testCases := []struct {
+		configEnabled bool
+		input         model.URLData
+		existing      *model.URL
+		expectLookup  bool
+		expectCreate  bool
+		expectedURL   string
+	}{
+		{
+			configEnabled: true,
+			input:         model.URLData{URL: "http://google.com"},
+			existing:      &existing,
+			expectLookup:  true,
+			expectedURL:   "localhost:8513/gclmd",
+		},
+		{
+			input:        model.URLData{URL: "http://google.com", Deduplicate: &enabled},
+			existing:     &existing,
+			expectLookup: true,
+			expectedURL:  "localhost:8513/gclmd",
+		},
+		{
+			configEnabled: true,
+			input:         model.URLData{URL: "http://google.com"},
+			expectLookup:  true,
+			expectCreate:  true,
+			expectedURL:   "localhost:8513/Xy12z",
+		},
+		{
+			configEnabled: true,
+			input:         model.URLData{URL: "http://google.com", Deduplicate: &disabled},
+			expectCreate:  true,
+			expectedURL:   "localhost:8513/Xy12z",
+		},
+		{
+			configEnabled: true,
+			input:         model.URLData{URL: "http://google.com", Alias: "my-google"},
+			expectCreate:  true,
+			expectedURL:   "localhost:8513/my-google",
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.SetupTest()
+		suite.service.cfg.Shortener.Deduplicate = tc.configEnabled
+		if tc.expectLookup {
+			if tc.existing != nil {
+				suite.mockRepo.On("FindByLongURL", ctx, "alice", "", canonicalURL).Return(tc.existing, nil).Once()
+			} else {
+				suite.mockRepo.On("FindByLongURL", ctx, "alice", "", canonicalURL).Return(nil, gorm.ErrRecordNotFound).Once()
+			}
+		}
+
+		suite.mockGen.On("GenerateShortURLCode").Return("Xy12z", nil)
+		suite.mockRepo.On("Create", ctx, testifyMock.MatchedBy(func(url *model.URL) bool {
+			return url.LongURLHash == model.HashLongURL(canonicalURL) && url.OwnerID == "alice"
+		})).Return(nil)
+		url, err := suite.service.CreateShortURL(ctx, tc.input)
+
+		require.NoError(err)
+		require.Equal(tc.expectedURL, url)
+		if !tc.expectLookup {
+			suite.mockRepo.AssertNotCalled(suite.T(), "FindByLongURL", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything, testifyMock.Anything)
+		}
+
+		if tc.expectCreate {
+			suite.mockRepo.AssertNumberOfCalls(suite.T(), "Create", 1)
+		} else {
+			suite.mockRepo.AssertNotCalled(suite.T(), "Create", testifyMock.Anything, testifyMock.Anything)
+		}
+	}
+}
+
 func (suite *URLServiceTestSuite) TestURLService_GetLongURL_ReadFromCache_Success() {
 	require := suite.Require()
 	testCases := []struct {
@@ -148,12 +465,12 @@ func (suite *URLServiceTestSuite) TestURLService_GetLongURL_ReadFromCache_Succes
 	}
 
 	for _, tc := range testCases {
-		suite.mockCacheRepo.On("Get", context.TODO(), tc.input).Return(&tc.expectedURL, nil).Once()
-		suite.mockRepo.On("FindByShortCode", context.TODO(), testifyMock.Anything).Times(0)
-		url, err := suite.service.GetLongURL(context.TODO(), tc.input)
+		suite.mockCacheRepo.On("Get", context.TODO(), "", tc.input).Return(&tc.expectedURL, nil).Once()
+		suite.mockRepo.On("FindByShortCode", context.TODO(), "", testifyMock.Anything).Times(0)
+		url, err := suite.service.GetLongURL(context.TODO(), model.RedirectRequest{ShortCode: tc.input})
 
 		require.NoError(err)
-		require.Equal(tc.expectedURL.LongURL, url)
+		require.Equal(tc.expectedURL.LongURL, url.LongURL)
 	}
 }
 
@@ -174,14 +491,98 @@ func (suite *URLServiceTestSuite) TestURLService_GetLongURL_ReadFromDb_Success()
 	}
 
 	for _, tc := range testCases {
-		suite.mockCacheRepo.On("Get", context.TODO(), tc.input).Return(nil, redis.Nil).Once()
-		suite.mockRepo.On("FindByShortCode", context.TODO(), tc.input).Return(&tc.expectedURL, nil).Once()
+		suite.mockCacheRepo.On("Get", context.TODO(), "", tc.input).Return(nil, redis.Nil).Once()
+		suite.mockRepo.On("FindByShortCode", testifyMock.Anything, "", tc.input).Return(&tc.expectedURL, nil).Once()
 		suite.mockCacheRepo.On("Set", context.TODO(), &tc.expectedURL).Return(nil)
-		url, err := suite.service.GetLongURL(context.TODO(), tc.input)
+		url, err := suite.service.GetLongURL(context.TODO(), model.RedirectRequest{ShortCode: tc.input})
+
+		require.NoError(err)
+		require.Equal(tc.expectedURL.LongURL, url.LongURL)
+	}
+}
+
+func (suite *URLServiceTestSuite) TestURLService_GetLongURL_ReadFromDb_PoolFull_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		input       string
+		expectedURL model.URL
+	}{
+		{
+			input: "G2ogLe",
+			expectedURL: model.URL{
+				LongURL:   "http://google.com",
+				ShortCode: "G2ogLe",
+				ID:        1,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.mockPool.ExpectedCalls = nil
+		suite.mockPool.On("Submit").Return(workerpool.ErrQueueFull).Once()
+		suite.mockCacheRepo.On("Get", context.TODO(), "", tc.input).Return(nil, redis.Nil).Once()
+		suite.mockRepo.On("FindByShortCode", testifyMock.Anything, "", tc.input).Return(&tc.expectedURL, nil).Once()
+		url, err := suite.service.GetLongURL(context.TODO(), model.RedirectRequest{ShortCode: tc.input})
+
+		require.NoError(err)
+		require.Equal(tc.expectedURL.LongURL, url.LongURL)
+		suite.mockCacheRepo.AssertNotCalled(suite.T(), "Set", testifyMock.Anything, testifyMock.Anything)
+	}
+}
+
+func (suite *URLServiceTestSuite) TestURLService_GetLongURL_RedirectCode_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		input        model.URL
+		defaultCode  int
+		expectedCode int
+	}{
+		{input: model.URL{ShortCode: "code1"}, expectedCode: http.StatusMovedPermanently},
+		{input: model.URL{ShortCode: "code2"}, defaultCode: http.StatusFound, expectedCode: http.StatusFound},
+		{input: model.URL{ShortCode: "code3"}, defaultCode: http.StatusOK, expectedCode: http.StatusMovedPermanently},
+		{input: model.URL{ShortCode: "code4", RedirectCode: http.StatusPermanentRedirect}, defaultCode: http.StatusFound, expectedCode: http.StatusPermanentRedirect},
+	}
+
+	for _, tc := range testCases {
+		suite.service.cfg.Shortener.RedirectCode = tc.defaultCode
+		tc.input.LongURL = "http://google.com"
+		suite.mockCacheRepo.On("Get", context.TODO(), "", tc.input.ShortCode).Return(&tc.input, nil).Once()
+		redirect, err := suite.service.GetLongURL(context.TODO(), model.RedirectRequest{ShortCode: tc.input.ShortCode})
+
+		require.NoError(err)
+		require.Equal(&model.Redirect{LongURL: "http://google.com", StatusCode: tc.expectedCode}, redirect)
+	}
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_RedirectCode() {
+	require := suite.Require()
+	testCases := []struct {
+		input int
+		err   error
+	}{
+		{input: http.StatusTemporaryRedirect},
+		{input: http.StatusSeeOther, err: ErrInvalidRedirectCode},
+		{input: http.StatusOK, err: ErrInvalidRedirectCode},
+	}
+
+	for _, tc := range testCases {
+		suite.mockGen.On("GenerateShortURLCode").Return("gclmd", nil).Once()
+		suite.mockRepo.On("Create", context.TODO(), testifyMock.MatchedBy(func(url *model.URL) bool {
+			return url.RedirectCode == tc.input
+		})).Return(nil).Once()
+		url, err := suite.service.CreateShortURL(context.TODO(), model.URLData{URL: "http://google.com", RedirectCode: tc.input})
+
+		if tc.err != nil {
+			require.ErrorIs(err, tc.err)
+			require.Empty(url)
+			continue
+		}
 
 		require.NoError(err)
-		require.Equal(tc.expectedURL.LongURL, url)
+		require.NotEmpty(url)
 	}
+
+	suite.mockRepo.AssertNumberOfCalls(suite.T(), "Create", 1)
 }
 
 func (suite *URLServiceTestSuite) TestURLService_GetLongURL_Failure() {
@@ -201,13 +602,94 @@ func (suite *URLServiceTestSuite) TestURLService_GetLongURL_Failure() {
 	}
 
 	for _, tc := range testCases {
-		suite.mockCacheRepo.On("Get", context.TODO(), tc.input).Return(nil, redis.Nil).Once()
-		suite.mockRepo.On("FindByShortCode", context.TODO(), tc.input).Return(nil, gorm.ErrRecordNotFound).Once()
-		url, err := suite.service.GetLongURL(context.TODO(), tc.input)
+		suite.mockCacheRepo.On("Get", context.TODO(), "", tc.input).Return(nil, redis.Nil).Once()
+		suite.mockRepo.On("FindByShortCode", testifyMock.Anything, "", tc.input).Return(nil, gorm.ErrRecordNotFound).Once()
+		suite.mockCacheRepo.On("SetMissing", testifyMock.Anything, "", tc.input).Return(nil).Once()
+		url, err := suite.service.GetLongURL(context.TODO(), model.RedirectRequest{ShortCode: tc.input})
 
-		require.Error(err)
+		require.ErrorIs(err, ErrURLNotFound)
+		require.Empty(url)
+		suite.mockCacheRepo.AssertCalled(suite.T(), "SetMissing", testifyMock.Anything, "", tc.input)
+	}
+}
+
+func (suite *URLServiceTestSuite) TestURLService_GetLongURL_CachedMissing_Failure() {
+	require := suite.Require()
+	suite.mockCacheRepo.On("Get", context.TODO(), "", "G2ogLe").Return(nil, model.ErrCachedMissing).Once()
+
+	url, err := suite.service.GetLongURL(context.TODO(), model.RedirectRequest{ShortCode: "G2ogLe"})
+
+	require.ErrorIs(err, ErrURLNotFound)
+	require.Empty(url)
+	suite.mockRepo.AssertNotCalled(suite.T(), "FindByShortCode", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_GetLongURL_CacheNotFound_Success() {
+	require := suite.Require()
+	// only the sentinel tells that a code is known missing, other errors are misses of the cache
+	suite.mockCacheRepo.On("Get", context.TODO(), "", "G2ogLe").Return(nil, gorm.ErrRecordNotFound).Once()
+	suite.mockRepo.On("FindByShortCode", testifyMock.Anything, "", "G2ogLe").Return(&model.URL{LongURL: "http://google.com/", ShortCode: "G2ogLe"}, nil).Once()
+	suite.mockCacheRepo.On("Set", testifyMock.Anything, testifyMock.Anything).Return(nil).Maybe()
+
+	redirect, err := suite.service.GetLongURL(context.TODO(), model.RedirectRequest{ShortCode: "G2ogLe"})
+
+	require.NoError(err)
+	require.Equal("http://google.com/", redirect.LongURL)
+}
+
+func (suite *URLServiceTestSuite) TestURLService_CreateShortURL_ForgetsMissing() {
+	require := suite.Require()
+	suite.mockGen.On("GenerateShortURLCode").Return("G2ogLe", nil).Once()
+	suite.mockRepo.On("Create", context.TODO(), testifyMock.Anything).Return(nil).Once()
+
+	_, err := suite.service.CreateShortURL(context.TODO(), model.URLData{URL: "http://google.com"})
+
+	require.NoError(err)
+	suite.mockCacheRepo.AssertCalled(suite.T(), "DeleteMissing", context.TODO(), "", []string{"G2ogLe"})
+}
+
+func (suite *URLServiceTestSuite) TestURLService_GetLongURL_Expired_Failure() {
+	require := suite.Require()
+	expiresAt := time.Now().Add(-time.Minute)
+	testCases := []struct {
+		input       string
+		expectedURL model.URL
+		fromCache   bool
+	}{
+		{
+			input: "G2ogLe",
+			expectedURL: model.URL{
+				LongURL:   "http://google.com",
+				ShortCode: "G2ogLe",
+				ExpiresAt: &expiresAt,
+			},
+			fromCache: true,
+		},
+		{
+			input: "G2ogLf",
+			expectedURL: model.URL{
+				LongURL:   "http://google.com",
+				ShortCode: "G2ogLf",
+				ExpiresAt: &expiresAt,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.fromCache {
+			suite.mockCacheRepo.On("Get", context.TODO(), "", tc.input).Return(&tc.expectedURL, nil).Once()
+		} else {
+			suite.mockCacheRepo.On("Get", context.TODO(), "", tc.input).Return(nil, redis.Nil).Once()
+			suite.mockRepo.On("FindByShortCode", testifyMock.Anything, "", tc.input).Return(&tc.expectedURL, nil).Once()
+		}
+
+		url, err := suite.service.GetLongURL(context.TODO(), model.RedirectRequest{ShortCode: tc.input})
+
+		require.ErrorIs(err, ErrURLExpired)
 		require.Empty(url)
 	}
+
+	suite.mockCacheRepo.AssertNotCalled(suite.T(), "Set", testifyMock.Anything, testifyMock.Anything)
 }
 
 func TestURLServiceTestSuite(t *testing.T) {
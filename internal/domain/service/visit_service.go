@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	"github.com/miladbarzideh/shortify/internal/infra"
+)
+
+const (
+	defaultStatsDays = 30
+	dayLayout        = "2006-01-02"
+	// unknownCountry is stored until a geo-IP lookup is available.
+	unknownCountry = "ZZ"
+)
+
+type VisitRepository interface {
+	Create(ctx context.Context, visit *model.Visit) error
+	CountByShortCode(ctx context.Context, domain string, shortCode string) (int64, int64, error)
+	DailyCounts(ctx context.Context, domain string, shortCode string, since time.Time) ([]model.DailyStats, error)
+}
+
+type VisitService struct {
+	logger        *logrus.Logger
+	cfg           *infra.Config
+	repo          VisitRepository
+	urlRepo       URLRepository
+	pool          WorkerPool
+	recordedCount infra.Counter
+	failedCount   infra.Counter
+}
+
+func NewVisitService(logger *logrus.Logger,
+	cfg *infra.Config,
+	repo VisitRepository,
+	urlRepo URLRepository,
+	pool WorkerPool,
+	telemetry *infra.TelemetryProvider,
+) *VisitService {
+	meter := telemetry.MeterProvider.Meter("visitService")
+	return &VisitService{
+		logger:        logger,
+		cfg:           cfg,
+		repo:          repo,
+		urlRepo:       urlRepo,
+		pool:          pool,
+		recordedCount: infra.NewCounter(meter, "visit.records"),
+		failedCount:   infra.NewCounter(meter, "visit.record_failures"),
+	}
+}
+
+// RecordVisit stores the visit in the background, so the redirect does not wait for the database.
+// Visits are dropped when the worker pool is saturated.
+func (svc *VisitService) RecordVisit(ctx context.Context, shortCode string, referrer string, userAgent string, clientIP string) {
+	visit := &model.Visit{
+		Domain:    DomainFromContext(ctx),
+		ShortCode: shortCode,
+		VisitedAt: time.Now().UTC(),
+		Referrer:  referrer,
+		UserAgent: userAgent,
+		IPHash:    svc.hashIP(clientIP),
+		Country:   unknownCountry,
+	}
+
+	err := svc.pool.Submit(func(ctx context.Context) {
+		if err := svc.repo.Create(ctx, visit); err != nil {
+			svc.failedCount.Inc(ctx)
+			svc.logger.Errorf("failed to record visit of '%s'. Error: %v", shortCode, err)
+			return
+		}
+
+		svc.recordedCount.Inc(ctx)
+	})
+	if err != nil {
+		svc.failedCount.Inc(ctx)
+		svc.logger.Warnf("skip recording visit of '%s'. Error: %v", shortCode, err)
+	}
+}
+
+func (svc *VisitService) GetStats(ctx context.Context, shortCode string) (*model.URLStats, error) {
+	domain := DomainFromContext(ctx)
+	if _, err := svc.urlRepo.FindByShortCode(ctx, domain, shortCode); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrURLNotFound
+		}
+
+		return nil, err
+	}
+
+	total, unique, err := svc.repo.CountByShortCode(ctx, domain, shortCode)
+	if err != nil {
+		return nil, err
+	}
+
+	since := svc.statsStart(time.Now().UTC())
+	daily, err := svc.repo.DailyCounts(ctx, domain, shortCode, since)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.URLStats{
+		ShortCode:      shortCode,
+		TotalClicks:    total,
+		UniqueVisitors: unique,
+		Daily:          fillDays(daily, since, time.Now().UTC()),
+	}, nil
+}
+
+func (svc *VisitService) statsStart(now time.Time) time.Time {
+	days := svc.cfg.Analytics.StatsDays
+	if days <= 0 {
+		days = defaultStatsDays
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return today.AddDate(0, 0, -(days - 1))
+}
+
+func (svc *VisitService) hashIP(ip string) string {
+	sum := sha256.Sum256([]byte(svc.cfg.Analytics.IPHashSalt + ip))
+	return hex.EncodeToString(sum[:])
+}
+
+// fillDays returns one entry per day in [since, until], using zero for days without visits.
+func fillDays(daily []model.DailyStats, since time.Time, until time.Time) []model.DailyStats {
+	clicks := make(map[string]int64, len(daily))
+	for _, d := range daily {
+		clicks[d.Day] = d.Clicks
+	}
+
+	var series []model.DailyStats
+	for day := since; !day.After(until); day = day.AddDate(0, 0, 1) {
+		key := day.Format(dayLayout)
+		series = append(series, model.DailyStats{Day: key, Clicks: clicks[key]})
+	}
+
+	return series
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/miladbarzideh/shortify/internal/domain/model"
+	genMock "github.com/miladbarzideh/shortify/internal/domain/service/mock"
+	"github.com/miladbarzideh/shortify/internal/infra"
+	"github.com/miladbarzideh/shortify/pkg/workerpool"
+)
+
+type VisitServiceTestSuite struct {
+	suite.Suite
+	service       *VisitService
+	mockVisitRepo *genMock.VisitRepository
+	mockRepo      *genMock.Repository
+	mockPool      *genMock.WorkerPool
+}
+
+func (suite *VisitServiceTestSuite) SetupTest() {
+	suite.mockVisitRepo = new(genMock.VisitRepository)
+	suite.mockRepo = new(genMock.Repository)
+	suite.mockPool = new(genMock.WorkerPool)
+	cfg := infra.Config{}
+	cfg.Analytics.IPHashSalt = "salt"
+	cfg.Analytics.StatsDays = 7
+	suite.service = NewVisitService(logrus.New(), &cfg, suite.mockVisitRepo, suite.mockRepo, suite.mockPool, infra.NOOPTelemetry)
+}
+
+func (suite *VisitServiceTestSuite) TestVisitService_RecordVisit_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		shortCode string
+		referrer  string
+		userAgent string
+		clientIP  string
+	}{
+		{
+			shortCode: "G2ogLe",
+			referrer:  "https://news.ycombinator.com",
+			userAgent: "curl/8.5.0",
+			clientIP:  "203.0.113.7",
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.mockPool.On("Submit").Return(nil).Once()
+		recorded := make(chan *model.Visit, 1)
+		suite.mockVisitRepo.On("Create", testifyMock.Anything, testifyMock.Anything).
+			Run(func(args testifyMock.Arguments) {
+				recorded <- args.Get(1).(*model.Visit)
+			}).Return(nil).Once()
+		suite.service.RecordVisit(context.TODO(), tc.shortCode, tc.referrer, tc.userAgent, tc.clientIP)
+
+		select {
+		case visit := <-recorded:
+			require.Equal(tc.shortCode, visit.ShortCode)
+			require.Equal(tc.referrer, visit.Referrer)
+			require.Equal(tc.userAgent, visit.UserAgent)
+			require.Equal(suite.service.hashIP(tc.clientIP), visit.IPHash)
+			require.NotContains(visit.IPHash, tc.clientIP)
+		case <-time.After(time.Second):
+			require.Fail("visit was not recorded")
+		}
+	}
+}
+
+func (suite *VisitServiceTestSuite) TestVisitService_RecordVisit_PoolFull_Failure() {
+	suite.mockPool.On("Submit").Return(workerpool.ErrQueueFull).Once()
+
+	suite.service.RecordVisit(context.TODO(), "G2ogLe", "", "curl/8.5.0", "203.0.113.7")
+
+	suite.mockVisitRepo.AssertNotCalled(suite.T(), "Create", testifyMock.Anything, testifyMock.Anything)
+}
+
+func (suite *VisitServiceTestSuite) TestVisitService_GetStats_Success() {
+	require := suite.Require()
+	today := time.Now().UTC().Format(dayLayout)
+	testCases := []struct {
+		input string
+		daily []model.DailyStats
+	}{
+		{
+			input: "G2ogLe",
+			daily: []model.DailyStats{{Day: today, Clicks: 5}},
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.mockRepo.On("FindByShortCode", context.TODO(), "", tc.input).Return(&model.URL{ShortCode: tc.input}, nil).Once()
+		suite.mockVisitRepo.On("CountByShortCode", context.TODO(), "", tc.input).Return(int64(5), int64(2), nil).Once()
+		suite.mockVisitRepo.On("DailyCounts", context.TODO(), "", tc.input, testifyMock.Anything).Return(tc.daily, nil).Once()
+		stats, err := suite.service.GetStats(context.TODO(), tc.input)
+
+		require.NoError(err)
+		require.Equal(int64(5), stats.TotalClicks)
+		require.Equal(int64(2), stats.UniqueVisitors)
+		require.Len(stats.Daily, 7)
+		require.Equal(model.DailyStats{Day: today, Clicks: 5}, stats.Daily[6])
+		require.Equal(int64(0), stats.Daily[0].Clicks)
+	}
+}
+
+func (suite *VisitServiceTestSuite) TestVisitService_GetStats_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		input       string
+		findErr     error
+		countErr    error
+		expectedErr error
+	}{
+		{
+			input:       "G2ogLe",
+			findErr:     gorm.ErrRecordNotFound,
+			expectedErr: ErrURLNotFound,
+		},
+		{
+			input:       "G2ogLf",
+			countErr:    gorm.ErrInvalidDB,
+			expectedErr: gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.findErr != nil {
+			suite.mockRepo.On("FindByShortCode", context.TODO(), "", tc.input).Return(nil, tc.findErr).Once()
+		} else {
+			suite.mockRepo.On("FindByShortCode", context.TODO(), "", tc.input).Return(&model.URL{ShortCode: tc.input}, nil).Once()
+			suite.mockVisitRepo.On("CountByShortCode", context.TODO(), "", tc.input).Return(int64(0), int64(0), tc.countErr).Once()
+		}
+
+		stats, err := suite.service.GetStats(context.TODO(), tc.input)
+
+		require.True(errors.Is(err, tc.expectedErr))
+		require.Nil(stats)
+	}
+}
+
+func (suite *VisitServiceTestSuite) TestVisitService_FillDays_Success() {
+	require := suite.Require()
+	since := time.Date(2024, time.May, 10, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, time.May, 12, 13, 0, 0, 0, time.UTC)
+	daily := []model.DailyStats{{Day: "2024-05-11", Clicks: 4}}
+
+	actual := fillDays(daily, since, until)
+
+	require.Equal([]model.DailyStats{
+		{Day: "2024-05-10", Clicks: 0},
+		{Day: "2024-05-11", Clicks: 4},
+		{Day: "2024-05-12", Clicks: 0},
+	}, actual)
+}
+
+func TestVisitServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(VisitServiceTestSuite))
+}
@@ -2,6 +2,7 @@ package infra
 
 import (
 	"errors"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -10,16 +11,23 @@ type Config struct {
 	Server     Server     `mapstructure:"server"`
 	Postgres   Postgres   `mapstructure:"postgres"`
 	Redis      Redis      `mapstructure:"redis"`
+	Cache      Cache      `mapstructure:"cache"`
 	Shortener  Shortener  `mapstructure:"shortener"`
 	WorkerPool WorkerPool `mapstructure:"worker_pool"`
+	Analytics  Analytics  `mapstructure:"analytics"`
+	RateLimit  RateLimit  `mapstructure:"rate_limit"`
 	Telemetry  Telemetry  `mapstructure:"telemetry"`
 }
 
 type Server struct {
 	AppVersion string `mapstructure:"app_version"`
 	Address    string `mapstructure:"address"`
+	BaseURL    string `mapstructure:"base_url"` // Public origin of short links, e.g. https://shfy.io
 	Port       string `mapstructure:"port"`
 	LogLevel   string `mapstructure:"log_level"`
+	// TrustedProxies are the CIDR ranges of the reverse proxies whose X-Forwarded-For header is trusted.
+	// Without any, the client IP is the address of the connection.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 type Postgres struct {
@@ -36,8 +44,76 @@ type Redis struct {
 	Password string `mapstructure:"password"`
 }
 
+type Cache struct {
+	Local LocalCache `mapstructure:"local"`
+	Lock  CacheLock  `mapstructure:"lock"`
+}
+
+// CacheLock lets one instance at a time read a code missing from the cache from the database, the others
+// waiting for it to fill the cache.
+type CacheLock struct {
+	Enabled bool          `mapstructure:"enabled"`
+	TTL     time.Duration `mapstructure:"ttl"`  // Expiry of the lock, in case its holder dies
+	Wait    time.Duration `mapstructure:"wait"` // How long to wait for the cache before reading the database anyway
+}
+
+// LocalCache is the in-process cache of each instance in front of the Redis cache.
+type LocalCache struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Size    int           `mapstructure:"size"` // Maximum number of links, the least recently used are evicted
+	TTL     time.Duration `mapstructure:"ttl"`  // Bounds how long an invalidation missed while disconnected from Redis goes unnoticed
+}
+
 type Shortener struct {
-	CodeLength int `mapstructure:"code_length"`
+	CodeLength    int           `mapstructure:"code_length"`
+	RedirectCode  int           `mapstructure:"redirect_code"`
+	Strategy      string        `mapstructure:"strategy"`
+	NodeID        int64         `mapstructure:"node_id"`
+	Salt          string        `mapstructure:"salt"`
+	Deduplicate   bool          `mapstructure:"deduplicate"`
+	Normalization Normalization `mapstructure:"normalization"`
+	MaxBatchSize  int           `mapstructure:"max_batch_size"`
+	MaxBatchBody  string        `mapstructure:"max_batch_body"` // Size limit of a batch request body, e.g. 2M
+	Growth        Growth        `mapstructure:"growth"`
+	Lockout       Lockout       `mapstructure:"lockout"`
+	Policy        Policy        `mapstructure:"policy"`
+	Bloom         Bloom         `mapstructure:"bloom"`
+}
+
+// Bloom sizes the filter of existing short codes that rejects unknown codes before the cache and the database.
+type Bloom struct {
+	Enabled           bool    `mapstructure:"enabled"`
+	ExpectedItems     uint64  `mapstructure:"expected_items"`
+	FalsePositiveRate float64 `mapstructure:"false_positive_rate"`
+}
+
+// Policy restricts the destinations links may point to.
+type Policy struct {
+	BlocklistFile   string   `mapstructure:"blocklist_file"` // One host per line, subdomains are blocked too
+	AllowlistFile   string   `mapstructure:"allowlist_file"` // When set, only the listed hosts are accepted
+	Patterns        []string `mapstructure:"patterns"`       // Regular expressions matched against the full URL
+	BlockPrivateIPs bool     `mapstructure:"block_private_ips"`
+	ResolveHosts    bool     `mapstructure:"resolve_hosts"` // Resolve host names to block those pointing to private IPs
+	CheckOnRedirect bool     `mapstructure:"check_on_redirect"`
+}
+
+// Lockout bounds the failed password attempts of a client on a protected link.
+type Lockout struct {
+	MaxAttempts int           `mapstructure:"max_attempts"`
+	Duration    time.Duration `mapstructure:"duration"`
+}
+
+type Growth struct {
+	Enabled            bool    `mapstructure:"enabled"`
+	CollisionThreshold float64 `mapstructure:"collision_threshold"`
+	MaxFillRatio       float64 `mapstructure:"max_fill_ratio"`
+	Window             int     `mapstructure:"window"`
+	MaxCodeLength      int     `mapstructure:"max_code_length"`
+}
+
+type Normalization struct {
+	StripFragment bool `mapstructure:"strip_fragment"`
+	SortQuery     bool `mapstructure:"sort_query"`
 }
 
 type WorkerPool struct {
@@ -45,6 +121,23 @@ type WorkerPool struct {
 	QueueSize   int `mapstructure:"queue_size"`
 }
 
+type Analytics struct {
+	IPHashSalt string `mapstructure:"ip_hash_salt"`
+	StatsDays  int    `mapstructure:"stats_days"`
+}
+
+type RateLimit struct {
+	Enabled  bool  `mapstructure:"enabled"`
+	Create   Limit `mapstructure:"create"`
+	Batch    Limit `mapstructure:"batch"` // counted in links rather than requests
+	Redirect Limit `mapstructure:"redirect"`
+}
+
+type Limit struct {
+	Requests int           `mapstructure:"requests"`
+	Window   time.Duration `mapstructure:"window"`
+}
+
 type Telemetry struct {
 	ServiceNamespaceKey string `mapstructure:"service_namespace_key"`
 	ServiceNameKey      string `mapstructure:"service_name_key"`
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 )
 
@@ -23,8 +24,8 @@ func NewCounter(meter metric.Meter, name string) Counter {
 	}
 }
 
-func (c Counter) Inc(ctx context.Context) {
-	c.counter.Add(ctx, 1)
+func (c Counter) Inc(ctx context.Context, opts ...metric.AddOption) {
+	c.counter.Add(ctx, 1, opts...)
 }
 
 type Latency struct {
@@ -42,18 +43,58 @@ func NewLatency(meter metric.Meter, name string) Latency {
 	}
 }
 
-func (l Latency) Record(ctx context.Context, start time.Time) {
-	l.histogram.Record(ctx, time.Since(start).Seconds())
+func (l Latency) Record(ctx context.Context, start time.Time, opts ...metric.RecordOption) {
+	l.histogram.Record(ctx, time.Since(start).Seconds(), opts...)
 }
 
+// CacheStats instruments one tier of the cache. Its metrics are shared by the tiers and carry a tier attribute,
+// e.g. cache.hits{tier="redis"}.
 type CacheStats struct {
-	Hits   Counter
-	Misses Counter
+	tier        metric.MeasurementOption
+	hits        Counter
+	misses      Counter
+	errors      Counter
+	setFailures Counter
+	getLatency  Latency
+	setLatency  Latency
 }
 
-func NewCacheStats(meter metric.Meter) CacheStats {
+func NewCacheStats(meter metric.Meter, tier string) CacheStats {
 	return CacheStats{
-		Hits:   NewCounter(meter, "cache.hits"),
-		Misses: NewCounter(meter, "cache.misses"),
+		tier:        metric.WithAttributes(attribute.String("tier", tier)),
+		hits:        NewCounter(meter, "cache.hits"),
+		misses:      NewCounter(meter, "cache.misses"),
+		errors:      NewCounter(meter, "cache.errors"),
+		setFailures: NewCounter(meter, "cache.set_failures"),
+		getLatency:  NewLatency(meter, "cache.get"),
+		setLatency:  NewLatency(meter, "cache.set"),
 	}
 }
+
+// Hit counts a read answered by the tier, including a code known to be missing.
+func (s CacheStats) Hit(ctx context.Context) {
+	s.hits.Inc(ctx, s.tier)
+}
+
+// Miss counts a read the tier had no entry for.
+func (s CacheStats) Miss(ctx context.Context) {
+	s.misses.Inc(ctx, s.tier)
+}
+
+// Error counts a read that failed.
+func (s CacheStats) Error(ctx context.Context) {
+	s.errors.Inc(ctx, s.tier)
+}
+
+// SetFailure counts a write that failed.
+func (s CacheStats) SetFailure(ctx context.Context) {
+	s.setFailures.Inc(ctx, s.tier)
+}
+
+func (s CacheStats) RecordGet(ctx context.Context, start time.Time) {
+	s.getLatency.Record(ctx, start, s.tier)
+}
+
+func (s CacheStats) RecordSet(ctx context.Context, start time.Time) {
+	s.setLatency.Record(ctx, start, s.tier)
+}
@@ -11,7 +11,8 @@ import (
 
 func NewPostgresConnection(cfg *Config) (*gorm.DB, error) {
 	db, err := gorm.Open(postgres.Open(buildDSN(cfg)), &gorm.Config{
-		Logger: logger.Default.LogMode(mapToDBLogLevel(cfg.Postgres.LogLevel)),
+		Logger:         logger.Default.LogMode(mapToDBLogLevel(cfg.Postgres.LogLevel)),
+		TranslateError: true,
 	})
 	if err != nil {
 		return nil, errors.New("database connection failed")
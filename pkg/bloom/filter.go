@@ -0,0 +1,199 @@
+package bloom
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// maxBits is the size limit of a Redis string, 512 MB.
+const maxBits = 1 << 32
+
+// buildingTTL drops the bitmap of a rebuild that died, so additions stop being written to it.
+const buildingTTL = time.Hour
+
+// ErrNotBuilt is returned when the filter has not been built yet, or was built with other parameters.
+var ErrNotBuilt = errors.New("bloom filter not built")
+
+// add sets the bits of the items, in the bitmap being rebuilt too when a rebuild is running,
+// so items added during a rebuild are not lost when it completes.
+//
+// KEYS[1]: bitmap, KEYS[2]: bitmap being rebuilt
+// ARGV: bit offsets
+var add = redis.NewScript(`
+local building = redis.call('EXISTS', KEYS[2]) == 1
+for i = 1, #ARGV do
+	redis.call('SETBIT', KEYS[1], ARGV[i], 1)
+	if building then
+		redis.call('SETBIT', KEYS[2], ARGV[i], 1)
+	end
+end
+return 0
+`)
+
+// Filter is a Bloom filter kept in a Redis bitmap, so it is shared by all instances of the app.
+// It tells whether an item may have been added, with false positives but no false negatives,
+// once it has been built with Rebuild.
+type Filter struct {
+	client *redis.Client
+	prefix string
+	bits   uint64
+	hashes int
+}
+
+// New returns a filter sized for expectedItems items with the given false positive rate.
+func New(client *redis.Client, prefix string, expectedItems uint64, falsePositiveRate float64) (*Filter, error) {
+	bits, hashes, err := Params(expectedItems, falsePositiveRate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Filter{
+		client: client,
+		prefix: prefix,
+		bits:   bits,
+		hashes: hashes,
+	}, nil
+}
+
+// Params returns the optimal number of bits and hash functions of a filter
+// holding n items with the false positive rate p.
+func Params(n uint64, p float64) (uint64, int, error) {
+	if n == 0 || p <= 0 || p >= 1 {
+		return 0, 0, fmt.Errorf("invalid bloom filter size: %d items with a false positive rate of %v", n, p)
+	}
+
+	bits := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	if bits > maxBits {
+		return 0, 0, fmt.Errorf("bloom filter of %d items with a false positive rate of %v exceeds %d bits", n, p, uint64(maxBits))
+	}
+
+	hashes := max(int(math.Round(bits/float64(n)*math.Ln2)), 1)
+
+	return uint64(bits), hashes, nil
+}
+
+// Add adds the items to the filter.
+func (f *Filter) Add(ctx context.Context, items ...string) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	offsets := make([]interface{}, 0, len(items)*f.hashes)
+	for _, item := range items {
+		for _, offset := range f.locations(item) {
+			offsets = append(offsets, offset)
+		}
+	}
+
+	return add.Run(ctx, f.client, []string{f.bitsKey(), f.buildingKey()}, offsets...).Err()
+}
+
+// MightContain reports whether the item may have been added. It returns true with ErrNotBuilt
+// until the filter has been built with its current parameters.
+func (f *Filter) MightContain(ctx context.Context, item string) (bool, error) {
+	pipe := f.client.Pipeline()
+	ready := pipe.Get(ctx, f.readyKey())
+	bits := make([]*redis.IntCmd, f.hashes)
+	for i, offset := range f.locations(item) {
+		bits[i] = pipe.GetBit(ctx, f.bitsKey(), int64(offset))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return true, err
+	}
+
+	if ready.Val() != f.version() {
+		return true, ErrNotBuilt
+	}
+
+	for _, bit := range bits {
+		if bit.Val() == 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// Rebuild builds the filter again from the items passed by load to its add function, and swaps it
+// in place of the current one once load returns. Items added meanwhile with Add are kept.
+func (f *Filter) Rebuild(ctx context.Context, load func(add func(items ...string) error) error) error {
+	building := f.buildingKey()
+	_, err := f.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, building)
+		// allocate the whole bitmap at once, it also marks the rebuild as running
+		pipe.SetBit(ctx, building, int64(f.bits-1), 0)
+		pipe.Expire(ctx, building, buildingTTL)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = load(func(items ...string) error {
+		pipe := f.client.Pipeline()
+		for _, item := range items {
+			for _, offset := range f.locations(item) {
+				pipe.SetBit(ctx, building, int64(offset), 1)
+			}
+		}
+
+		pipe.Expire(ctx, building, buildingTTL)
+		_, err := pipe.Exec(ctx)
+		return err
+	})
+	if err != nil {
+		f.client.Del(ctx, building)
+		return err
+	}
+
+	_, err = f.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Rename(ctx, building, f.bitsKey())
+		pipe.Persist(ctx, f.bitsKey())
+		pipe.Set(ctx, f.readyKey(), f.version(), 0)
+		return nil
+	})
+
+	return err
+}
+
+// locations returns the bit offsets of the item, derived by double hashing from the two halves of its FNV-1a hash.
+func (f *Filter) locations(item string) []uint64 {
+	h := fnv.New128a()
+	_, _ = h.Write([]byte(item))
+	sum := h.Sum(nil)
+	h1, h2 := binary.BigEndian.Uint64(sum[:8]), binary.BigEndian.Uint64(sum[8:])
+	offsets := make([]uint64, f.hashes)
+	for i := range offsets {
+		offsets[i] = (h1 + uint64(i)*h2) % f.bits
+	}
+
+	return offsets
+}
+
+// version identifies the parameters the bitmap was built with, as the offsets of an item depend on them.
+func (f *Filter) version() string {
+	return strconv.FormatUint(f.bits, 10) + ":" + strconv.Itoa(f.hashes)
+}
+
+// The keys share a hash tag, so the scripts and transactions touching several of them work on Redis Cluster.
+
+func (f *Filter) bitsKey() string {
+	return fmt.Sprintf("{%s}:bits", f.prefix)
+}
+
+func (f *Filter) buildingKey() string {
+	return fmt.Sprintf("{%s}:building", f.prefix)
+}
+
+func (f *Filter) readyKey() string {
+	return fmt.Sprintf("{%s}:ready", f.prefix)
+}
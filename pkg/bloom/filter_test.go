@@ -0,0 +1,177 @@
+package bloom
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/suite"
+)
+
+type FilterTestSuite struct {
+	suite.Suite
+	filter *Filter
+	mock   redismock.ClientMock
+}
+
+func (suite *FilterTestSuite) SetupTest() {
+	client, mock := redismock.NewClientMock()
+	filter, err := New(client, "bloom", 1000, 0.01)
+	suite.Require().NoError(err)
+	suite.filter = filter
+	suite.mock = mock
+}
+
+func (suite *FilterTestSuite) TestParams() {
+	require := suite.Require()
+	testCases := []struct {
+		items          uint64
+		rate           float64
+		expectedBits   uint64
+		expectedHashes int
+		expectedErr    bool
+	}{
+		{items: 1000, rate: 0.01, expectedBits: 9586, expectedHashes: 7},
+		{items: 1_000_000, rate: 0.001, expectedBits: 14_377_588, expectedHashes: 10},
+		{items: 10, rate: 0.5, expectedBits: 15, expectedHashes: 1},
+		{items: 0, rate: 0.01, expectedErr: true},
+		{items: 1000, rate: 0, expectedErr: true},
+		{items: 1000, rate: 1, expectedErr: true},
+		{items: 1 << 40, rate: 0.01, expectedErr: true},
+	}
+
+	for _, tc := range testCases {
+		bits, hashes, err := Params(tc.items, tc.rate)
+
+		if tc.expectedErr {
+			require.Error(err)
+			continue
+		}
+
+		require.NoError(err)
+		require.Equal(tc.expectedBits, bits)
+		require.Equal(tc.expectedHashes, hashes)
+	}
+}
+
+func (suite *FilterTestSuite) TestLocations() {
+	require := suite.Require()
+	offsets := suite.filter.locations("G2ogLe")
+
+	require.Len(offsets, suite.filter.hashes)
+	require.Equal(offsets, suite.filter.locations("G2ogLe"))
+	require.NotEqual(offsets, suite.filter.locations("G2ogLf"))
+	for _, offset := range offsets {
+		require.Less(offset, suite.filter.bits)
+	}
+}
+
+func (suite *FilterTestSuite) TestAdd() {
+	require := suite.Require()
+	var offsets []interface{}
+	for _, item := range []string{"G2ogLe", "go.example.com/G2ogLe"} {
+		for _, offset := range suite.filter.locations(item) {
+			offsets = append(offsets, offset)
+		}
+	}
+
+	suite.mock.ExpectEvalSha(add.Hash(), []string{"{bloom}:bits", "{bloom}:building"}, offsets...).SetVal(int64(0))
+	err := suite.filter.Add(context.TODO(), "G2ogLe", "go.example.com/G2ogLe")
+
+	require.NoError(err)
+	require.NoError(suite.filter.Add(context.TODO()))
+	require.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *FilterTestSuite) TestMightContain() {
+	require := suite.Require()
+	testCases := []struct {
+		name        string
+		ready       string
+		unsetBit    int
+		expected    bool
+		expectedErr error
+	}{
+		{name: "contained", ready: suite.filter.version(), unsetBit: -1, expected: true},
+		{name: "one bit unset", ready: suite.filter.version(), unsetBit: 3, expected: false},
+		{name: "not built", unsetBit: 0, expected: true, expectedErr: ErrNotBuilt},
+		{name: "built with other parameters", ready: "100:2", unsetBit: 0, expected: true, expectedErr: ErrNotBuilt},
+	}
+
+	for _, tc := range testCases {
+		suite.SetupTest()
+		if tc.ready == "" {
+			suite.mock.ExpectGet("{bloom}:ready").RedisNil()
+		} else {
+			suite.mock.ExpectGet("{bloom}:ready").SetVal(tc.ready)
+		}
+		for i, offset := range suite.filter.locations("G2ogLe") {
+			bit := int64(1)
+			if i == tc.unsetBit {
+				bit = 0
+			}
+			suite.mock.ExpectGetBit("{bloom}:bits", int64(offset)).SetVal(bit)
+		}
+		actual, err := suite.filter.MightContain(context.TODO(), "G2ogLe")
+
+		require.Equal(tc.expected, actual, tc.name)
+		require.Equal(tc.expectedErr, err, tc.name)
+	}
+}
+
+func (suite *FilterTestSuite) TestMightContain_Failure() {
+	require := suite.Require()
+	suite.mock.ExpectGet("{bloom}:ready").SetErr(errors.New("FAIL"))
+	actual, err := suite.filter.MightContain(context.TODO(), "G2ogLe")
+
+	require.True(actual)
+	require.Error(err)
+	require.NotErrorIs(err, ErrNotBuilt)
+}
+
+func (suite *FilterTestSuite) TestRebuild() {
+	require := suite.Require()
+	suite.expectStart()
+	for _, offset := range suite.filter.locations("G2ogLe") {
+		suite.mock.ExpectSetBit("{bloom}:building", int64(offset), 1).SetVal(0)
+	}
+	suite.mock.ExpectExpire("{bloom}:building", buildingTTL).SetVal(true)
+	suite.mock.ExpectTxPipeline()
+	suite.mock.ExpectRename("{bloom}:building", "{bloom}:bits").SetVal("OK")
+	suite.mock.ExpectPersist("{bloom}:bits").SetVal(true)
+	suite.mock.ExpectSet("{bloom}:ready", suite.filter.version(), 0).SetVal("OK")
+	suite.mock.ExpectTxPipelineExec()
+
+	err := suite.filter.Rebuild(context.TODO(), func(add func(items ...string) error) error {
+		return add("G2ogLe")
+	})
+
+	require.NoError(err)
+	require.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *FilterTestSuite) TestRebuild_LoadFailure() {
+	require := suite.Require()
+	suite.expectStart()
+	suite.mock.ExpectDel("{bloom}:building").SetVal(1)
+
+	err := suite.filter.Rebuild(context.TODO(), func(add func(items ...string) error) error {
+		return errors.New("connection refused")
+	})
+
+	require.Error(err)
+	require.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *FilterTestSuite) expectStart() {
+	suite.mock.ExpectTxPipeline()
+	suite.mock.ExpectDel("{bloom}:building").SetVal(0)
+	suite.mock.ExpectSetBit("{bloom}:building", int64(suite.filter.bits-1), 0).SetVal(0)
+	suite.mock.ExpectExpire("{bloom}:building", buildingTTL).SetVal(true)
+	suite.mock.ExpectTxPipelineExec()
+}
+
+func TestFilterTestSuite(t *testing.T) {
+	suite.Run(t, new(FilterTestSuite))
+}
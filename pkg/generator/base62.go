@@ -1,8 +1,11 @@
 package generator
 
 import (
+	"context"
 	"math/rand"
 	"regexp"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -10,33 +13,65 @@ const base62Charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ01234
 
 var base62Regex = regexp.MustCompile("^[a-zA-Z0-9]+$")
 
+// RandomGenerator generates codes with math/rand. It is fast but its codes are predictable, see SecureGenerator.
 type RandomGenerator struct {
+	mu     sync.Mutex
 	rand   *rand.Rand
-	length int
+	length atomic.Int64
 }
 
 func NewGenerator(length int) *RandomGenerator {
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	return &RandomGenerator{
-		rand:   r,
-		length: length,
+	g := &RandomGenerator{
+		rand: r,
 	}
+	g.length.Store(int64(length))
+
+	return g
 }
 
-func (g *RandomGenerator) GenerateShortURLCode() string {
-	result := make([]byte, g.length)
-	for i := 0; i < g.length; i++ {
+func (g *RandomGenerator) GenerateShortURLCode(_ context.Context) (string, error) {
+	length := g.Length()
+	result := make([]byte, length)
+	// rand.Rand is not safe for concurrent use
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i := 0; i < length; i++ {
 		charIndex := g.rand.Intn(len(base62Charset))
 		result[i] = base62Charset[charIndex]
 	}
 
-	return string(result)
+	return string(result), nil
+}
+
+// EncodeBase62 encodes n with the given 62 characters alphabet, left padded with its zero digit to minLength.
+func EncodeBase62(n uint64, alphabet string, minLength int) string {
+	var digits []byte
+	for n > 0 {
+		digits = append(digits, alphabet[n%62])
+		n /= 62
+	}
+
+	for len(digits) < minLength {
+		digits = append(digits, alphabet[0])
+	}
+
+	for i, j := 0, len(digits)-1; i < j; i, j = i+1, j-1 {
+		digits[i], digits[j] = digits[j], digits[i]
+	}
+
+	return string(digits)
 }
 
 func IsValidBase62(s string) bool {
 	return base62Regex.MatchString(s)
 }
 
+func (g *RandomGenerator) Length() int {
+	return int(g.length.Load())
+}
+
+// SetLength changes the length of the codes generated from now on. It is safe to call concurrently.
 func (g *RandomGenerator) SetLength(length int) {
-	g.length = length
+	g.length.Store(int64(length))
 }
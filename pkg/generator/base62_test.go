@@ -1,6 +1,8 @@
 package generator
 
 import (
+	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -40,13 +42,34 @@ func (suite *GeneratorTestSuite) TestGenerator_GenerateShortURLCode_Success() {
 
 	for _, tc := range testCases {
 		suite.generator.SetLength(tc.input)
-		actual := suite.generator.GenerateShortURLCode()
+		require.Equal(tc.input, suite.generator.Length())
+		actual, err := suite.generator.GenerateShortURLCode(context.TODO())
 
+		require.NoError(err)
 		require.Equal(tc.input, len(actual))
 		require.Equal(tc.expected, actual)
 	}
 }
 
+func (suite *GeneratorTestSuite) TestGenerator_GenerateShortURLCode_Concurrent() {
+	require := suite.Require()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				actual, err := suite.generator.GenerateShortURLCode(context.TODO())
+
+				require.NoError(err)
+				require.Len(actual, 7)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
 func (suite *GeneratorTestSuite) TestGenerator_IsValidBase62_Success() {
 	require := suite.Require()
 	testCases := []struct {
@@ -67,6 +90,24 @@ func (suite *GeneratorTestSuite) TestGenerator_IsValidBase62_Success() {
 	}
 }
 
+func (suite *GeneratorTestSuite) TestGenerator_EncodeBase62_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		input     uint64
+		minLength int
+		expected  string
+	}{
+		{input: 0, minLength: 0, expected: ""},
+		{input: 61, minLength: 0, expected: "9"},
+		{input: 62, minLength: 0, expected: "ba"},
+		{input: 62, minLength: 5, expected: "aaaba"},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(tc.expected, EncodeBase62(tc.input, base62Charset, tc.minLength))
+	}
+}
+
 func TestGeneratorTestSuite(t *testing.T) {
 	suite.Run(t, new(GeneratorTestSuite))
 }
@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SecureGeneratorTestSuite struct {
+	suite.Suite
+}
+
+func (suite *SecureGeneratorTestSuite) TestSecureGenerator_GenerateShortURLCode_Success() {
+	require := suite.Require()
+	gen := NewSecureGenerator(7)
+	seen := make(map[string]struct{})
+	for i := 0; i < 1000; i++ {
+		actual, err := gen.GenerateShortURLCode(context.TODO())
+
+		require.NoError(err)
+		require.Len(actual, 7)
+		require.True(IsValidBase62(actual))
+		_, ok := seen[actual]
+		require.False(ok)
+		seen[actual] = struct{}{}
+	}
+}
+
+func (suite *SecureGeneratorTestSuite) TestSecureGenerator_RejectionSampling_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		input    []byte
+		length   int
+		expected string
+	}{
+		{input: []byte{0, 61, 62, 247}, length: 4, expected: "a9a9"},
+		{input: []byte{255, 248, 1, 250, 63}, length: 2, expected: "bb"},
+		{input: []byte{252, 253, 254, 255, 5, 6, 7}, length: 1, expected: "f"},
+	}
+
+	for _, tc := range testCases {
+		gen := NewSecureGenerator(tc.length)
+		gen.reader = bytes.NewReader(append(tc.input, make([]byte, 16)...))
+		actual, err := gen.GenerateShortURLCode(context.TODO())
+
+		require.NoError(err)
+		require.Equal(tc.expected, actual)
+	}
+}
+
+func (suite *SecureGeneratorTestSuite) TestSecureGenerator_GenerateShortURLCode_Failure() {
+	require := suite.Require()
+	gen := NewSecureGenerator(7)
+	gen.reader = iotest.ErrReader(errors.New("entropy unavailable"))
+	actual, err := gen.GenerateShortURLCode(context.TODO())
+
+	require.Error(err)
+	require.Empty(actual)
+}
+
+func TestSecureGeneratorTestSuite(t *testing.T) {
+	suite.Run(t, new(SecureGeneratorTestSuite))
+}
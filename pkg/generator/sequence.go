@@ -0,0 +1,116 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"math/bits"
+)
+
+// maxObfuscatedLength is the longest code whose space, 62^10, fits in an uint64.
+const maxObfuscatedLength = 10
+
+// obfuscationMultiplier is odd and not a multiple of 31, so it is coprime with every 62^n
+// and multiplying by it permutes [0, 62^n).
+const obfuscationMultiplier = 1_000_000_007
+
+var ErrSequenceExhausted = errors.New("sequence exhausted")
+
+// Sequence returns unique, increasing numbers, e.g. from a database sequence.
+type Sequence interface {
+	Next(ctx context.Context) (uint64, error)
+}
+
+// CounterGenerator encodes the next value of a sequence in base62. Codes never collide,
+// but they are predictable and reveal how many links exist.
+type CounterGenerator struct {
+	seq       Sequence
+	minLength int
+}
+
+func NewCounterGenerator(seq Sequence, minLength int) *CounterGenerator {
+	return &CounterGenerator{
+		seq:       seq,
+		minLength: minLength,
+	}
+}
+
+func (g *CounterGenerator) GenerateShortURLCode(ctx context.Context) (string, error) {
+	n, err := g.seq.Next(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return EncodeBase62(n, base62Charset, g.minLength), nil
+}
+
+// ObfuscatedGenerator is a hashids-style generator: the next value of a sequence is permuted within
+// the codes of its length and encoded with an alphabet shuffled by a salt. Codes never collide
+// and consecutive links get unrelated codes.
+type ObfuscatedGenerator struct {
+	seq       Sequence
+	alphabet  string
+	minLength int
+}
+
+func NewObfuscatedGenerator(seq Sequence, salt string, minLength int) *ObfuscatedGenerator {
+	return &ObfuscatedGenerator{
+		seq:       seq,
+		alphabet:  shuffle(base62Charset, salt),
+		minLength: min(max(minLength, 1), maxObfuscatedLength),
+	}
+}
+
+func (g *ObfuscatedGenerator) GenerateShortURLCode(ctx context.Context) (string, error) {
+	n, err := g.seq.Next(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return g.encode(n)
+}
+
+func (g *ObfuscatedGenerator) encode(n uint64) (string, error) {
+	length, space := g.minLength, pow62(g.minLength)
+	for n >= space {
+		if length == maxObfuscatedLength {
+			return "", ErrSequenceExhausted
+		}
+
+		length++
+		space *= 62
+	}
+
+	// each length has its own permutation, so codes of different lengths cannot collide either
+	hi, lo := bits.Mul64(n, obfuscationMultiplier)
+	_, permuted := bits.Div64(hi%space, lo, space)
+
+	return EncodeBase62(permuted, g.alphabet, length), nil
+}
+
+func pow62(n int) uint64 {
+	result := uint64(1)
+	for i := 0; i < n; i++ {
+		result *= 62
+	}
+
+	return result
+}
+
+// shuffle deterministically permutes the alphabet with the salt, as hashids does.
+func shuffle(alphabet string, salt string) string {
+	if salt == "" {
+		return alphabet
+	}
+
+	result := []byte(alphabet)
+
+	for i, v, p := len(result)-1, 0, 0; i > 0; i-- {
+		v %= len(salt)
+		p += int(salt[v])
+		j := (int(salt[v]) + v + p) % i
+		result[i], result[j] = result[j], result[i]
+		v++
+	}
+
+	return string(result)
+}
@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeSequence returns consecutive numbers starting at next.
+type fakeSequence struct {
+	next uint64
+	err  error
+}
+
+func (s *fakeSequence) Next(_ context.Context) (uint64, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	s.next++
+	return s.next - 1, nil
+}
+
+type SequenceGeneratorTestSuite struct {
+	suite.Suite
+}
+
+func (suite *SequenceGeneratorTestSuite) TestCounterGenerator_GenerateShortURLCode_Success() {
+	require := suite.Require()
+	gen := NewCounterGenerator(&fakeSequence{next: 61}, 3)
+	testCases := []string{"aa9", "aba", "abb"}
+
+	for _, expected := range testCases {
+		actual, err := gen.GenerateShortURLCode(context.TODO())
+
+		require.NoError(err)
+		require.Equal(expected, actual)
+	}
+}
+
+func (suite *SequenceGeneratorTestSuite) TestObfuscatedGenerator_GenerateShortURLCode_Success() {
+	require := suite.Require()
+	seq := &fakeSequence{next: 1}
+	gen := NewObfuscatedGenerator(seq, "shortify", 2)
+	seen := make(map[string]struct{})
+	previous := ""
+	// covers the whole space of 2 characters codes and the switch to 3 characters
+	for i := 0; i < 62*62+100; i++ {
+		actual, err := gen.GenerateShortURLCode(context.TODO())
+
+		require.NoError(err)
+		require.True(IsValidBase62(actual))
+		_, ok := seen[actual]
+		require.False(ok)
+		require.NotEqual(previous, actual)
+		seen[actual] = struct{}{}
+		previous = actual
+	}
+
+	require.Len(previous, 3)
+}
+
+func (suite *SequenceGeneratorTestSuite) TestObfuscatedGenerator_Salt_Success() {
+	require := suite.Require()
+	first, err := NewObfuscatedGenerator(&fakeSequence{next: 1}, "salt-a", 5).GenerateShortURLCode(context.TODO())
+	require.NoError(err)
+	second, err := NewObfuscatedGenerator(&fakeSequence{next: 1}, "salt-b", 5).GenerateShortURLCode(context.TODO())
+	require.NoError(err)
+
+	require.Len(first, 5)
+	require.NotEqual(first, second)
+}
+
+func (suite *SequenceGeneratorTestSuite) TestSequenceGenerators_Failure() {
+	require := suite.Require()
+	seq := &fakeSequence{err: errors.New("connection refused")}
+
+	_, err := NewCounterGenerator(seq, 3).GenerateShortURLCode(context.TODO())
+	require.Error(err)
+	_, err = NewObfuscatedGenerator(seq, "salt", 3).GenerateShortURLCode(context.TODO())
+	require.Error(err)
+	_, err = NewObfuscatedGenerator(&fakeSequence{next: pow62(maxObfuscatedLength)}, "salt", 3).GenerateShortURLCode(context.TODO())
+	require.ErrorIs(err, ErrSequenceExhausted)
+}
+
+func TestSequenceGeneratorTestSuite(t *testing.T) {
+	suite.Run(t, new(SequenceGeneratorTestSuite))
+}
@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	nodeBits     = 10
+	sequenceBits = 12
+	maxNodeID    = 1<<nodeBits - 1
+	maxSequence  = 1<<sequenceBits - 1
+)
+
+var ErrClockMovedBackwards = errors.New("clock moved backwards")
+
+// SnowflakeGenerator builds 63 bits IDs out of the milliseconds since the epoch, the node ID and a
+// per-millisecond sequence, encoded in base62. Codes never collide as long as every instance of the
+// app has a distinct node ID, and no database round trip is needed.
+type SnowflakeGenerator struct {
+	mu       sync.Mutex
+	epoch    time.Time
+	nodeID   int64
+	lastTime int64
+	sequence int64
+	now      func() time.Time
+}
+
+func NewSnowflakeGenerator(nodeID int64, epoch time.Time) (*SnowflakeGenerator, error) {
+	if nodeID < 0 || nodeID > maxNodeID {
+		return nil, fmt.Errorf("node id must be between 0 and %d", maxNodeID)
+	}
+
+	return &SnowflakeGenerator{
+		epoch:  epoch,
+		nodeID: nodeID,
+		now:    time.Now,
+	}, nil
+}
+
+func (g *SnowflakeGenerator) GenerateShortURLCode(_ context.Context) (string, error) {
+	id, err := g.nextID()
+	if err != nil {
+		return "", err
+	}
+
+	return EncodeBase62(uint64(id), base62Charset, 0), nil
+}
+
+func (g *SnowflakeGenerator) nextID() (int64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.now().Sub(g.epoch).Milliseconds()
+	if now < g.lastTime {
+		return 0, fmt.Errorf("%w by %dms", ErrClockMovedBackwards, g.lastTime-now)
+	}
+
+	if now == g.lastTime {
+		g.sequence = (g.sequence + 1) & maxSequence
+		if g.sequence == 0 {
+			// sequence exhausted for this millisecond
+			for now <= g.lastTime {
+				time.Sleep(100 * time.Microsecond)
+				now = g.now().Sub(g.epoch).Milliseconds()
+			}
+		}
+	} else {
+		g.sequence = 0
+	}
+
+	g.lastTime = now
+
+	return now<<(nodeBits+sequenceBits) | g.nodeID<<sequenceBits | g.sequence, nil
+}
@@ -0,0 +1,62 @@
+package generator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SnowflakeGeneratorTestSuite struct {
+	suite.Suite
+}
+
+func (suite *SnowflakeGeneratorTestSuite) TestSnowflakeGenerator_GenerateShortURLCode_Success() {
+	require := suite.Require()
+	epoch := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	gen, err := NewSnowflakeGenerator(7, epoch)
+	require.NoError(err)
+	now := epoch.Add(time.Hour)
+	gen.now = func() time.Time { return now }
+
+	first, err := gen.nextID()
+	require.NoError(err)
+	second, err := gen.nextID()
+	require.NoError(err)
+
+	require.Equal(time.Hour.Milliseconds()<<22|7<<12, first)
+	require.Equal(first+1, second)
+
+	seen := make(map[string]struct{})
+	gen.now = time.Now
+	for i := 0; i < 10000; i++ {
+		code, err := gen.GenerateShortURLCode(context.TODO())
+
+		require.NoError(err)
+		_, ok := seen[code]
+		require.False(ok)
+		seen[code] = struct{}{}
+	}
+}
+
+func (suite *SnowflakeGeneratorTestSuite) TestSnowflakeGenerator_Failure() {
+	require := suite.Require()
+	_, err := NewSnowflakeGenerator(maxNodeID+1, time.Now())
+	require.Error(err)
+
+	epoch := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	gen, err := NewSnowflakeGenerator(1, epoch)
+	require.NoError(err)
+	gen.now = func() time.Time { return epoch.Add(time.Hour) }
+	_, err = gen.nextID()
+	require.NoError(err)
+
+	gen.now = func() time.Time { return epoch.Add(time.Minute) }
+	_, err = gen.nextID()
+	require.ErrorIs(err, ErrClockMovedBackwards)
+}
+
+func TestSnowflakeGeneratorTestSuite(t *testing.T) {
+	suite.Run(t, new(SnowflakeGeneratorTestSuite))
+}
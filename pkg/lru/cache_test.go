@@ -0,0 +1,85 @@
+package lru
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheTestSuite struct {
+	suite.Suite
+	cache *Cache[string, int]
+	now   time.Time
+}
+
+func (suite *CacheTestSuite) SetupTest() {
+	suite.cache = New[string, int](2)
+	suite.now = time.Date(2024, time.May, 10, 12, 0, 0, 0, time.UTC)
+	suite.cache.now = func() time.Time { return suite.now }
+}
+
+func (suite *CacheTestSuite) TestCache_GetSet() {
+	require := suite.Require()
+	suite.cache.Set("a", 1, time.Minute)
+
+	value, ok := suite.cache.Get("a")
+	require.True(ok)
+	require.Equal(1, value)
+
+	_, ok = suite.cache.Get("b")
+	require.False(ok)
+
+	suite.cache.Set("a", 2, time.Minute)
+	value, _ = suite.cache.Get("a")
+	require.Equal(2, value)
+	require.Equal(1, suite.cache.Len())
+}
+
+func (suite *CacheTestSuite) TestCache_EvictsLeastRecentlyUsed() {
+	require := suite.Require()
+	suite.cache.Set("a", 1, time.Minute)
+	suite.cache.Set("b", 2, time.Minute)
+	suite.cache.Get("a")
+	suite.cache.Set("c", 3, time.Minute)
+
+	_, ok := suite.cache.Get("b")
+	require.False(ok)
+	_, ok = suite.cache.Get("a")
+	require.True(ok)
+	_, ok = suite.cache.Get("c")
+	require.True(ok)
+	require.Equal(2, suite.cache.Len())
+}
+
+func (suite *CacheTestSuite) TestCache_Expiry() {
+	require := suite.Require()
+	suite.cache.Set("a", 1, time.Minute)
+	suite.cache.Set("b", 2, 0)
+
+	suite.now = suite.now.Add(59 * time.Second)
+	_, ok := suite.cache.Get("a")
+	require.True(ok)
+	_, ok = suite.cache.Get("b")
+	require.False(ok)
+
+	suite.now = suite.now.Add(time.Second)
+	_, ok = suite.cache.Get("a")
+	require.False(ok)
+	require.Zero(suite.cache.Len())
+}
+
+func (suite *CacheTestSuite) TestCache_Delete() {
+	require := suite.Require()
+	suite.cache.Set("a", 1, time.Minute)
+	suite.cache.Delete("a")
+	suite.cache.Delete("b")
+
+	_, ok := suite.cache.Get("a")
+	require.False(ok)
+	require.Zero(suite.cache.Len())
+}
+
+func TestCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(CacheTestSuite))
+}
@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindow approximates a sliding window with the counters of the current and previous fixed windows:
+// the previous count is weighted by the share of the sliding window that still overlaps it.
+// The request is counted only when allowed, so rejected requests do not extend the penalty.
+//
+// KEYS[1]: counter of the current window, KEYS[2]: counter of the previous window
+// ARGV[1]: limit, ARGV[2]: window in milliseconds, ARGV[3]: milliseconds elapsed in the current window,
+// ARGV[4]: cost of the request
+// Returns {allowed, current count, previous count}
+var slidingWindow = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local elapsed = tonumber(ARGV[3])
+local cost = tonumber(ARGV[4])
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+local previous = tonumber(redis.call('GET', KEYS[2]) or '0')
+if previous * (window - elapsed) / window + current + cost > limit then
+	return {0, current, previous}
+end
+current = redis.call('INCRBY', KEYS[1], cost)
+if current == cost then
+	redis.call('PEXPIRE', KEYS[1], window * 2)
+end
+return {1, current, previous}
+`)
+
+// Limit allows Requests per sliding Window.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the current fixed window ends
+	Reset time.Duration
+	// RetryAfter is the time until a rejected request would be allowed, zero when allowed
+	RetryAfter time.Duration
+}
+
+// Limiter counts requests per key in Redis, so the budget is shared by all instances of the app.
+type Limiter struct {
+	client *redis.Client
+	prefix string
+	now    func() time.Time
+}
+
+func New(client *redis.Client, prefix string) *Limiter {
+	return &Limiter{
+		client: client,
+		prefix: prefix,
+		now:    time.Now,
+	}
+}
+
+// Allow counts a request of key against the limit.
+func (l *Limiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	return l.AllowN(ctx, key, limit, 1)
+}
+
+// AllowN counts a request of key that costs n requests against the limit, such as a batch of n items.
+func (l *Limiter) AllowN(ctx context.Context, key string, limit Limit, n int) (Result, error) {
+	window := limit.Window.Milliseconds()
+	if limit.Requests <= 0 || window <= 0 {
+		return Result{}, fmt.Errorf("invalid limit: %d requests per %s", limit.Requests, limit.Window)
+	}
+
+	if n <= 0 || n > limit.Requests {
+		return Result{}, fmt.Errorf("invalid cost: %d of %d requests", n, limit.Requests)
+	}
+
+	now := l.now().UnixMilli()
+	index, elapsed := now/window, now%window
+	keys := []string{l.buildKey(key, index), l.buildKey(key, index-1)}
+	values, err := slidingWindow.Run(ctx, l.client, keys, limit.Requests, window, elapsed, n).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	if len(values) != 3 {
+		return Result{}, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	allowed, current, previous := values[0] == 1, values[1], values[2]
+	weight := float64(window-elapsed) / float64(window)
+	used := int(math.Ceil(float64(previous)*weight + float64(current)))
+	result := Result{
+		Allowed:   allowed,
+		Limit:     limit.Requests,
+		Remaining: max(limit.Requests-used, 0),
+		Reset:     time.Duration(window-elapsed) * time.Millisecond,
+	}
+
+	if !allowed {
+		result.RetryAfter = retryAfter(int64(limit.Requests), int64(n), current, previous, window, elapsed)
+	}
+
+	return result, nil
+}
+
+// retryAfter returns how long until a request of the cost fits in the sliding window.
+func retryAfter(limit, cost, current, previous, window, elapsed int64) time.Duration {
+	var wait float64
+	if current+cost > limit {
+		// the current window becomes the previous one, whose weight must then drop enough
+		wait = float64(window-elapsed) + float64(window)*(1-float64(limit-cost)/float64(current))
+	} else {
+		wait = float64(window)*(1-float64(limit-cost-current)/float64(previous)) - float64(elapsed)
+	}
+
+	return time.Duration(math.Ceil(max(wait, 1))) * time.Millisecond
+}
+
+// buildKey keeps both windows of a key in the same Redis Cluster slot with a hash tag.
+func (l *Limiter) buildKey(key string, index int64) string {
+	return fmt.Sprintf("%s:{%s}:%d", l.prefix, key, index)
+}
@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/suite"
+)
+
+type LimiterTestSuite struct {
+	suite.Suite
+	limiter *Limiter
+	mock    redismock.ClientMock
+}
+
+func (suite *LimiterTestSuite) SetupTest() {
+	client, mock := redismock.NewClientMock()
+	suite.limiter = New(client, "ratelimit")
+	// 250ms into the window of index 1000
+	suite.limiter.now = func() time.Time { return time.UnixMilli(1000*1000 + 250) }
+	suite.mock = mock
+}
+
+func (suite *LimiterTestSuite) TestLimiter_Allow_Success() {
+	require := suite.Require()
+	limit := Limit{Requests: 10, Window: time.Second}
+	testCases := []struct {
+		reply    []interface{}
+		expected Result
+	}{
+		{
+			reply:    []interface{}{int64(1), int64(1), int64(0)},
+			expected: Result{Allowed: true, Limit: 10, Remaining: 9, Reset: 750 * time.Millisecond},
+		},
+		{
+			// 4 * 0.75 + 5 = 8 used
+			reply:    []interface{}{int64(1), int64(5), int64(4)},
+			expected: Result{Allowed: true, Limit: 10, Remaining: 2, Reset: 750 * time.Millisecond},
+		},
+		{
+			// full current window: wait for the window to end and the previous one to weigh at most 9 of 10
+			reply:    []interface{}{int64(0), int64(10), int64(0)},
+			expected: Result{Limit: 10, Reset: 750 * time.Millisecond, RetryAfter: 850 * time.Millisecond},
+		},
+		{
+			// 8 * 0.75 + 4 = 10 used: wait until 8 * weight <= 5, i.e. 375ms into the window
+			reply:    []interface{}{int64(0), int64(4), int64(8)},
+			expected: Result{Limit: 10, Reset: 750 * time.Millisecond, RetryAfter: 125 * time.Millisecond},
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.mock.ExpectEvalSha(slidingWindow.Hash(), []string{"ratelimit:{ip:203.0.113.7}:1000", "ratelimit:{ip:203.0.113.7}:999"},
+			10, int64(1000), int64(250), 1).SetVal(tc.reply)
+		result, err := suite.limiter.Allow(context.TODO(), "ip:203.0.113.7", limit)
+
+		require.NoError(err)
+		require.Equal(tc.expected, result)
+		require.NoError(suite.mock.ExpectationsWereMet())
+	}
+}
+
+func (suite *LimiterTestSuite) TestLimiter_AllowN_Success() {
+	require := suite.Require()
+	limit := Limit{Requests: 10, Window: time.Second}
+	testCases := []struct {
+		n        int
+		reply    []interface{}
+		expected Result
+	}{
+		{
+			n:        4,
+			reply:    []interface{}{int64(1), int64(4), int64(0)},
+			expected: Result{Allowed: true, Limit: 10, Remaining: 6, Reset: 750 * time.Millisecond},
+		},
+		{
+			// 7 of the current window + 4 > 10: wait for the window to end and 7 * weight <= 6
+			n:        4,
+			reply:    []interface{}{int64(0), int64(7), int64(0)},
+			expected: Result{Limit: 10, Remaining: 3, Reset: 750 * time.Millisecond, RetryAfter: 893 * time.Millisecond},
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.mock.ExpectEvalSha(slidingWindow.Hash(), []string{"ratelimit:{owner:alice}:1000", "ratelimit:{owner:alice}:999"},
+			10, int64(1000), int64(250), tc.n).SetVal(tc.reply)
+		result, err := suite.limiter.AllowN(context.TODO(), "owner:alice", limit, tc.n)
+
+		require.NoError(err)
+		require.Equal(tc.expected, result)
+		require.NoError(suite.mock.ExpectationsWereMet())
+	}
+}
+
+func (suite *LimiterTestSuite) TestLimiter_Allow_Failure() {
+	require := suite.Require()
+	_, err := suite.limiter.Allow(context.TODO(), "ip:203.0.113.7", Limit{})
+	require.Error(err)
+
+	_, err = suite.limiter.AllowN(context.TODO(), "ip:203.0.113.7", Limit{Requests: 10, Window: time.Second}, 11)
+	require.Error(err)
+
+	suite.mock.ExpectEvalSha(slidingWindow.Hash(), []string{"ratelimit:{ip:203.0.113.7}:1000", "ratelimit:{ip:203.0.113.7}:999"},
+		10, int64(1000), int64(250), 1).SetErr(errors.New("connection refused"))
+	_, err = suite.limiter.Allow(context.TODO(), "ip:203.0.113.7", Limit{Requests: 10, Window: time.Second})
+	require.Error(err)
+}
+
+func TestLimiterTestSuite(t *testing.T) {
+	suite.Run(t, new(LimiterTestSuite))
+}
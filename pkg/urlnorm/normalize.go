@@ -0,0 +1,102 @@
+package urlnorm
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+var (
+	ErrInvalidURL        = errors.New("invalid url")
+	ErrUnsupportedScheme = errors.New("unsupported scheme")
+)
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// hostProfile maps IDN hosts to punycode like a lookup would, but tolerates underscores
+// that appear in real world host names.
+var hostProfile = idna.New(idna.MapForLookup(), idna.StrictDomainName(false), idna.Transitional(false))
+
+type Options struct {
+	StripFragment bool
+	SortQuery     bool
+}
+
+// Normalize returns the canonical form of an http(s) URL: lowercase scheme and host,
+// punycode host, no default port and "/" for an empty path. Fragments and query
+// parameter order are only touched when enabled in opts.
+func Normalize(raw string, opts Options) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if _, ok := defaultPorts[scheme]; !ok {
+		return "", fmt.Errorf("%w: '%s'", ErrUnsupportedScheme, u.Scheme)
+	}
+
+	host, err := normalizeHost(u.Hostname())
+	if err != nil {
+		return "", err
+	}
+
+	port := u.Port()
+	if port == defaultPorts[scheme] {
+		port = ""
+	}
+
+	u.Scheme = scheme
+	u.Host = joinHostPort(host, port)
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	if opts.StripFragment {
+		u.Fragment = ""
+		u.RawFragment = ""
+	}
+
+	if opts.SortQuery && u.RawQuery != "" {
+		// Encode sorts the parameters by key and keeps the order of repeated keys
+		u.RawQuery = u.Query().Encode()
+	}
+
+	return u.String(), nil
+}
+
+func normalizeHost(host string) (string, error) {
+	if host == "" {
+		return "", fmt.Errorf("%w: missing host", ErrInvalidURL)
+	}
+
+	if net.ParseIP(host) != nil {
+		return strings.ToLower(host), nil
+	}
+
+	ascii, err := hostProfile.ToASCII(strings.ToLower(host))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	return ascii, nil
+}
+
+func joinHostPort(host string, port string) string {
+	if port != "" {
+		return net.JoinHostPort(host, port)
+	}
+
+	if strings.Contains(host, ":") {
+		return "[" + host + "]"
+	}
+
+	return host
+}
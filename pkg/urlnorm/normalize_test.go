@@ -0,0 +1,65 @@
+package urlnorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type NormalizeTestSuite struct {
+	suite.Suite
+}
+
+func (suite *NormalizeTestSuite) TestNormalize_Success() {
+	require := suite.Require()
+	testCases := []struct {
+		input    string
+		opts     Options
+		expected string
+	}{
+		{input: "HTTPS://Google.COM/Search?q=Go", expected: "https://google.com/Search?q=Go"},
+		{input: "  http://google.com  ", expected: "http://google.com/"},
+		{input: "http://google.com:80/a", expected: "http://google.com/a"},
+		{input: "https://google.com:443/a", expected: "https://google.com/a"},
+		{input: "https://google.com:8443/a", expected: "https://google.com:8443/a"},
+		{input: "http://[::1]:80/a", expected: "http://[::1]/a"},
+		{input: "http://[::1]:8080/a", expected: "http://[::1]:8080/a"},
+		{input: "https://bücher.example/katalog", expected: "https://xn--bcher-kva.example/katalog"},
+		{input: "https://google.com/a#top", expected: "https://google.com/a#top"},
+		{input: "https://google.com/a#top", opts: Options{StripFragment: true}, expected: "https://google.com/a"},
+		{input: "https://google.com/a?b=2&a=1", expected: "https://google.com/a?b=2&a=1"},
+		{input: "https://google.com/a?b=2&a=1&a=0", opts: Options{SortQuery: true}, expected: "https://google.com/a?a=1&a=0&b=2"},
+	}
+
+	for _, tc := range testCases {
+		actual, err := Normalize(tc.input, tc.opts)
+
+		require.NoError(err)
+		require.Equal(tc.expected, actual)
+	}
+}
+
+func (suite *NormalizeTestSuite) TestNormalize_Failure() {
+	require := suite.Require()
+	testCases := []struct {
+		input       string
+		expectedErr error
+	}{
+		{input: "javascript:alert(1)", expectedErr: ErrUnsupportedScheme},
+		{input: "file:///etc/passwd", expectedErr: ErrUnsupportedScheme},
+		{input: "ftp://example.com/file", expectedErr: ErrUnsupportedScheme},
+		{input: "google.com", expectedErr: ErrUnsupportedScheme},
+		{input: "https:///path", expectedErr: ErrInvalidURL},
+		{input: "http://[::1", expectedErr: ErrInvalidURL},
+	}
+
+	for _, tc := range testCases {
+		_, err := Normalize(tc.input, Options{})
+
+		require.ErrorIs(err, tc.expectedErr)
+	}
+}
+
+func TestNormalizeTestSuite(t *testing.T) {
+	suite.Run(t, new(NormalizeTestSuite))
+}
@@ -0,0 +1,224 @@
+package urlpolicy
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Rules that can reject a destination, reported in a Violation.
+const (
+	RuleBlockedHost = "blocked_host"
+	RuleNotAllowed  = "not_allowed"
+	RulePattern     = "pattern"
+	RulePrivateIP   = "private_ip"
+)
+
+var ErrInvalidURL = errors.New("invalid url")
+
+// Violation is returned for a destination rejected by a rule.
+type Violation struct {
+	Rule   string
+	Detail string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("%s: %s", v.Rule, v.Detail)
+}
+
+// Resolver looks up the addresses of a host, net.DefaultResolver satisfies it.
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network string, host string) ([]netip.Addr, error)
+}
+
+type Options struct {
+	// BlockedHosts rejects the hosts and their subdomains.
+	BlockedHosts []string
+	// AllowedHosts, when not empty, rejects every host that is not one of them or their subdomains.
+	AllowedHosts []string
+	// Patterns are regular expressions rejecting the full URLs they match.
+	Patterns []string
+	// BlockPrivateIPs rejects loopback, private, link-local and unspecified addresses, local host names
+	// and numeric hosts that browsers read as an IP address.
+	BlockPrivateIPs bool
+	// Resolver, when set with BlockPrivateIPs, also rejects host names resolving to such an address.
+	Resolver Resolver
+}
+
+// Policy decides whether a URL is an acceptable destination. It is safe for concurrent use.
+type Policy struct {
+	blocked         map[string]struct{}
+	allowed         map[string]struct{}
+	patterns        []*regexp.Regexp
+	blockPrivateIPs bool
+	resolver        Resolver
+}
+
+func New(opts Options) (*Policy, error) {
+	p := &Policy{
+		blocked:         hostSet(opts.BlockedHosts),
+		allowed:         hostSet(opts.AllowedHosts),
+		blockPrivateIPs: opts.BlockPrivateIPs,
+		resolver:        opts.Resolver,
+	}
+
+	for _, pattern := range opts.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+
+		p.patterns = append(p.patterns, re)
+	}
+
+	return p, nil
+}
+
+// Check returns a *Violation if the URL must not be used as a destination.
+func (p *Policy) Check(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return fmt.Errorf("%w: missing host", ErrInvalidURL)
+	}
+
+	if matched, ok := MatchHost(p.blocked, host); ok {
+		return &Violation{Rule: RuleBlockedHost, Detail: fmt.Sprintf("'%s' is blocked", matched)}
+	}
+
+	if len(p.allowed) > 0 {
+		if _, ok := MatchHost(p.allowed, host); !ok {
+			return &Violation{Rule: RuleNotAllowed, Detail: fmt.Sprintf("'%s' is not allowed", host)}
+		}
+	}
+
+	for _, re := range p.patterns {
+		if re.MatchString(rawURL) {
+			return &Violation{Rule: RulePattern, Detail: fmt.Sprintf("matches %q", re.String())}
+		}
+	}
+
+	if p.blockPrivateIPs {
+		return p.checkPrivate(ctx, host)
+	}
+
+	return nil
+}
+
+func (p *Policy) checkPrivate(ctx context.Context, host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if isPrivate(addr) {
+			return &Violation{Rule: RulePrivateIP, Detail: fmt.Sprintf("'%s' is not a public address", host)}
+		}
+
+		return nil
+	}
+
+	if isLocalName(host) {
+		return &Violation{Rule: RulePrivateIP, Detail: fmt.Sprintf("'%s' is a local host name", host)}
+	}
+
+	if isNumericHost(host) {
+		return &Violation{Rule: RulePrivateIP, Detail: fmt.Sprintf("'%s' is a numeric address", host)}
+	}
+
+	if p.resolver == nil {
+		return nil
+	}
+
+	addrs, err := p.resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		// an unresolvable host cannot reach an internal address now; it is checked again on redirect if enabled
+		return nil
+	}
+
+	for _, addr := range addrs {
+		if isPrivate(addr) {
+			return &Violation{Rule: RulePrivateIP, Detail: fmt.Sprintf("'%s' resolves to %s", host, addr)}
+		}
+	}
+
+	return nil
+}
+
+func isPrivate(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsUnspecified() || addr.IsMulticast()
+}
+
+func isLocalName(host string) bool {
+	return host == "localhost" || strings.HasSuffix(host, ".localhost") ||
+		strings.HasSuffix(host, ".local") || strings.HasSuffix(host, ".internal") || !strings.Contains(host, ".")
+}
+
+// isNumericHost reports hosts ending in a number, such as 2130706433 or 0x7f.1, that browsers parse as an IPv4 address.
+func isNumericHost(host string) bool {
+	last := host[strings.LastIndexByte(host, '.')+1:]
+	digits := "0123456789"
+	if hex, ok := strings.CutPrefix(last, "0x"); ok {
+		last, digits = hex, "0123456789abcdef"
+	}
+
+	return strings.Trim(last, digits) == ""
+}
+
+// MatchHost returns the entry of hosts that is the host or one of its parent domains.
+func MatchHost(hosts map[string]struct{}, host string) (string, bool) {
+	for {
+		if _, ok := hosts[host]; ok {
+			return host, true
+		}
+
+		i := strings.IndexByte(host, '.')
+		if i == -1 {
+			return "", false
+		}
+
+		host = host[i+1:]
+	}
+}
+
+// LoadHosts reads a host list file: one host per line, blank lines and lines starting with # are ignored.
+func LoadHosts(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var hosts []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		hosts = append(hosts, line)
+	}
+
+	return hosts, scanner.Err()
+}
+
+func hostSet(hosts []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(hosts))
+	for _, host := range hosts {
+		host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+		if host != "" {
+			set[host] = struct{}{}
+		}
+	}
+
+	return set
+}
@@ -0,0 +1,110 @@
+package urlpolicy
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PolicyTestSuite struct {
+	suite.Suite
+}
+
+// fakeResolver resolves hosts from a fixed table.
+type fakeResolver map[string][]netip.Addr
+
+func (r fakeResolver) LookupNetIP(_ context.Context, _ string, host string) ([]netip.Addr, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+
+	return addrs, nil
+}
+
+func (suite *PolicyTestSuite) TestPolicy_Check() {
+	require := suite.Require()
+	policy, err := New(Options{
+		BlockedHosts:    []string{"Evil.com", "phish.example."},
+		Patterns:        []string{`(?i)/wp-login\.php`},
+		BlockPrivateIPs: true,
+		Resolver: fakeResolver{
+			"rebind.example.org": {netip.MustParseAddr("10.0.0.1")},
+			"google.com":         {netip.MustParseAddr("142.250.74.14")},
+		},
+	})
+	require.NoError(err)
+	testCases := []struct {
+		input string
+		rule  string
+	}{
+		{input: "https://google.com/"},
+		{input: "https://notevil.com/"},
+		{input: "https://unresolvable.example.net/"},
+		{input: "https://face.bead/"},
+		{input: "https://evil.com/", rule: RuleBlockedHost},
+		{input: "https://login.EVIL.com./a", rule: RuleBlockedHost},
+		{input: "https://phish.example/", rule: RuleBlockedHost},
+		{input: "https://google.com/WP-Login.php", rule: RulePattern},
+		{input: "http://127.0.0.1:8080/", rule: RulePrivateIP},
+		{input: "http://[::1]/", rule: RulePrivateIP},
+		{input: "http://[::ffff:192.168.1.1]/", rule: RulePrivateIP},
+		{input: "http://169.254.169.254/latest/meta-data", rule: RulePrivateIP},
+		{input: "http://localhost/", rule: RulePrivateIP},
+		{input: "http://printer.local/", rule: RulePrivateIP},
+		{input: "http://2130706433/", rule: RulePrivateIP},
+		{input: "http://0x7f.1/", rule: RulePrivateIP},
+		{input: "https://rebind.example.org/", rule: RulePrivateIP},
+	}
+
+	for _, tc := range testCases {
+		err := policy.Check(context.TODO(), tc.input)
+
+		if tc.rule == "" {
+			require.NoError(err, tc.input)
+			continue
+		}
+
+		var violation *Violation
+		require.ErrorAs(err, &violation, tc.input)
+		require.Equal(tc.rule, violation.Rule, tc.input)
+	}
+}
+
+func (suite *PolicyTestSuite) TestPolicy_Check_Allowlist() {
+	require := suite.Require()
+	policy, err := New(Options{AllowedHosts: []string{"example.com"}})
+	require.NoError(err)
+
+	require.NoError(policy.Check(context.TODO(), "https://example.com/"))
+	require.NoError(policy.Check(context.TODO(), "https://docs.example.com/"))
+	var violation *Violation
+	require.ErrorAs(policy.Check(context.TODO(), "https://example.org/"), &violation)
+	require.Equal(RuleNotAllowed, violation.Rule)
+}
+
+func (suite *PolicyTestSuite) TestNew_InvalidPattern() {
+	_, err := New(Options{Patterns: []string{"("}})
+
+	suite.Require().Error(err)
+}
+
+func (suite *PolicyTestSuite) TestLoadHosts() {
+	require := suite.Require()
+	path := filepath.Join(suite.T().TempDir(), "blocklist.txt")
+	require.NoError(os.WriteFile(path, []byte("# phishing\nevil.com\n\n  phish.example  \n"), 0o600))
+
+	hosts, err := LoadHosts(path)
+
+	require.NoError(err)
+	require.Equal([]string{"evil.com", "phish.example"}, hosts)
+}
+
+func TestPolicyTestSuite(t *testing.T) {
+	suite.Run(t, new(PolicyTestSuite))
+}
@@ -0,0 +1,132 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	ErrQueueFull = errors.New("worker pool queue is full")
+	ErrClosed    = errors.New("worker pool is closed")
+)
+
+// Pool runs submitted jobs on a fixed number of workers backed by a bounded queue.
+// Submit never blocks: when the queue is full the job is dropped and ErrQueueFull is returned,
+// so callers on a request path decide themselves whether to skip the work or run it inline.
+type Pool struct {
+	logger    *logrus.Logger
+	queue     chan func(ctx context.Context)
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	mu        sync.RWMutex
+	closed    bool
+	processed metric.Int64Counter
+	dropped   metric.Int64Counter
+}
+
+func New(logger *logrus.Logger, workerCount int, queueSize int, meter metric.Meter) *Pool {
+	workerCount = max(workerCount, 1)
+	queueSize = max(queueSize, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool{
+		logger:    logger,
+		queue:     make(chan func(ctx context.Context), queueSize),
+		ctx:       ctx,
+		cancel:    cancel,
+		processed: newCounter(meter, "workerpool.processed", "total number of processed jobs"),
+		dropped:   newCounter(meter, "workerpool.dropped", "total number of jobs dropped because the queue was full"),
+	}
+
+	_, err := meter.Int64ObservableGauge("workerpool.queue_depth",
+		metric.WithDescription("number of jobs waiting in the queue"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			o.Observe(int64(len(p.queue)))
+			return nil
+		}),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	for i := 0; i < workerCount; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+
+	return p
+}
+
+// Submit enqueues the job without blocking. The job receives a context that is only
+// canceled when Shutdown gives up waiting for the queue to drain.
+func (p *Pool) Submit(job func(ctx context.Context)) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrClosed
+	}
+
+	select {
+	case p.queue <- job:
+		return nil
+	default:
+		p.dropped.Add(p.ctx, 1)
+		return ErrQueueFull
+	}
+}
+
+// Shutdown stops accepting jobs and waits until the queued jobs are processed or ctx is done.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return ctx.Err()
+	}
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for job := range p.queue {
+		p.run(job)
+	}
+}
+
+func (p *Pool) run(job func(ctx context.Context)) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.logger.Errorf("worker pool job panicked: %v", r)
+		}
+	}()
+
+	job(p.ctx)
+	p.processed.Add(p.ctx, 1)
+}
+
+func newCounter(meter metric.Meter, name string, description string) metric.Int64Counter {
+	counter, err := meter.Int64Counter(name, metric.WithDescription(description))
+	if err != nil {
+		panic(err)
+	}
+
+	return counter
+}
@@ -0,0 +1,100 @@
+package workerpool
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+type PoolTestSuite struct {
+	suite.Suite
+}
+
+func (suite *PoolTestSuite) TestPool_Submit_Success() {
+	require := suite.Require()
+	pool := New(logrus.New(), 2, 10, noop.NewMeterProvider().Meter("test"))
+	var processed atomic.Int32
+	for i := 0; i < 10; i++ {
+		err := pool.Submit(func(ctx context.Context) {
+			processed.Add(1)
+		})
+		require.NoError(err)
+	}
+
+	err := pool.Shutdown(context.Background())
+
+	require.NoError(err)
+	require.Equal(int32(10), processed.Load())
+}
+
+func (suite *PoolTestSuite) TestPool_Submit_QueueFull_Failure() {
+	require := suite.Require()
+	pool := New(logrus.New(), 1, 1, noop.NewMeterProvider().Meter("test"))
+	started := make(chan struct{})
+	release := make(chan struct{})
+	require.NoError(pool.Submit(func(ctx context.Context) {
+		close(started)
+		<-release
+	}))
+	<-started
+	require.NoError(pool.Submit(func(ctx context.Context) {}))
+
+	err := pool.Submit(func(ctx context.Context) {})
+
+	require.ErrorIs(err, ErrQueueFull)
+	close(release)
+	require.NoError(pool.Shutdown(context.Background()))
+}
+
+func (suite *PoolTestSuite) TestPool_Submit_Closed_Failure() {
+	require := suite.Require()
+	pool := New(logrus.New(), 1, 1, noop.NewMeterProvider().Meter("test"))
+	require.NoError(pool.Shutdown(context.Background()))
+
+	err := pool.Submit(func(ctx context.Context) {})
+
+	require.ErrorIs(err, ErrClosed)
+}
+
+func (suite *PoolTestSuite) TestPool_Shutdown_Timeout_Failure() {
+	require := suite.Require()
+	pool := New(logrus.New(), 1, 0, noop.NewMeterProvider().Meter("test"))
+	started := make(chan struct{})
+	require.Eventually(func() bool {
+		return pool.Submit(func(ctx context.Context) {
+			close(started)
+			<-ctx.Done()
+		}) == nil
+	}, time.Second, time.Millisecond)
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := pool.Shutdown(ctx)
+
+	require.ErrorIs(err, context.DeadlineExceeded)
+}
+
+func (suite *PoolTestSuite) TestPool_Run_Panic_Success() {
+	require := suite.Require()
+	pool := New(logrus.New(), 1, 2, noop.NewMeterProvider().Meter("test"))
+	var processed atomic.Int32
+	require.NoError(pool.Submit(func(ctx context.Context) {
+		panic("boom")
+	}))
+	require.NoError(pool.Submit(func(ctx context.Context) {
+		processed.Add(1)
+	}))
+
+	require.NoError(pool.Shutdown(context.Background()))
+	require.Equal(int32(1), processed.Load())
+}
+
+func TestPoolTestSuite(t *testing.T) {
+	suite.Run(t, new(PoolTestSuite))
+}